@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ConradIrwin/conl-go/schema"
+	"github.com/ConradIrwin/conl-lsp/lsp"
+)
+
+// A schemaKey identifies a version of a schema: the version of the open
+// document, or the modification time of the file on disk.
+type schemaKey struct {
+	uri     lsp.DocumentURI
+	version string
+}
+
+// An analysis is the result of validating a version of a document.
+// It is shared by diagnostics, hover and completion.
+type analysis struct {
+	version int32
+	schema  schemaKey
+	result  *schema.ValidationResult
+}
+
+type parsedSchema struct {
+	version string
+	schema  *schema.Schema
+	err     error
+}
+
+// analyze validates doc against its schema, reusing the previous result if
+// neither the document nor the schema has changed since. Only open documents
+// are cached, as the version of a document read from disk is meaningless.
+func (s *Server) analyze(ctx context.Context, snap *snapshot, doc *TextDocument) *analysis {
+	open := snap.docs[doc.URI] == doc
+	if open {
+		s.mutex.RLock()
+		cached, ok := s.analyses[doc.URI]
+		s.mutex.RUnlock()
+		if ok && cached.version == doc.Version && cached.schema.version == s.schemaVersion(snap, cached.schema.uri) {
+			return cached
+		}
+	}
+
+	a := &analysis{version: doc.Version}
+	a.result = schema.Validate([]byte(doc.Content), func(name string) (*schema.Schema, error) {
+		result, key, err := s.loadSchema(ctx, snap, doc.URI, name)
+		a.schema = key
+		return result, err
+	})
+	if open && ctx.Err() == nil {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		if cached, ok := s.analyses[doc.URI]; !ok || cached.version <= doc.Version {
+			s.analyses[doc.URI] = a
+		}
+	}
+	return a
+}
+
+// schemaVersion returns the current version of the schema at uri.
+func (s *Server) schemaVersion(snap *snapshot, uri lsp.DocumentURI) string {
+	if uri == "" {
+		return ""
+	}
+	if doc, ok := snap.docs[uri]; ok {
+		return fmt.Sprintf("open@%d", doc.Version)
+	}
+	u := uri.URL()
+	if u.Scheme == "file" {
+		info, err := os.Stat(u.Path)
+		if err != nil {
+			return "missing"
+		}
+		return fmt.Sprintf("file@%d:%d", info.ModTime().UnixNano(), info.Size())
+	}
+	return u.Scheme
+}
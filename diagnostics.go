@@ -0,0 +1,430 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ConradIrwin/conl-lsp/lsp"
+)
+
+// diagnosticsDelay is how long to wait after an edit before validating,
+// so that a burst of keystrokes causes only one validation.
+var diagnosticsDelay = 150 * time.Millisecond
+
+// A diagnosticsScheduler validates documents in the background and publishes
+// the results. Scheduling a document again before its validation finishes
+// cancels the earlier validation, and diagnostics are never published for
+// an older version of a document than was last published.
+type diagnosticsScheduler struct {
+	validate func(ctx context.Context, doc *TextDocument) []lsp.Diagnostic
+	publish  func(params *lsp.PublishDiagnosticsParams)
+	logger   *slog.Logger
+
+	mutex sync.Mutex
+	docs  map[lsp.DocumentURI]*scheduledDiagnostics
+	// pending tracks scheduled and running validations so that shutdown can wait for them
+	pending sync.WaitGroup
+}
+
+type scheduledDiagnostics struct {
+	// generation is incremented each time the document is scheduled, so
+	// that superseded validations can tell that they should not publish.
+	generation int
+	timer      *time.Timer
+	cancel     context.CancelFunc
+	published  int32
+}
+
+func newDiagnosticsScheduler(logger *slog.Logger, validate func(ctx context.Context, doc *TextDocument) []lsp.Diagnostic, publish func(params *lsp.PublishDiagnosticsParams)) *diagnosticsScheduler {
+	return &diagnosticsScheduler{
+		validate: validate,
+		publish:  publish,
+		logger:   logger,
+		docs:     map[lsp.DocumentURI]*scheduledDiagnostics{},
+	}
+}
+
+// schedule validates doc after delay, replacing any pending or running
+// validation of the same document.
+func (d *diagnosticsScheduler) schedule(doc *TextDocument, delay time.Duration) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	scheduled, ok := d.docs[doc.URI]
+	if !ok {
+		scheduled = &scheduledDiagnostics{published: -1 << 31}
+		d.docs[doc.URI] = scheduled
+	}
+	d.stop(scheduled)
+	scheduled.generation++
+	generation := scheduled.generation
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scheduled.cancel = cancel
+	d.pending.Add(1)
+	scheduled.timer = time.AfterFunc(delay, func() {
+		defer d.pending.Done()
+		defer cancel()
+		d.run(ctx, doc, generation)
+	})
+}
+
+func (d *diagnosticsScheduler) run(ctx context.Context, doc *TextDocument, generation int) {
+	defer logPanic(d.logger)
+
+	diagnostics := d.validate(ctx, doc)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	scheduled, ok := d.docs[doc.URI]
+	if ctx.Err() != nil || !ok || scheduled.generation != generation || doc.Version < scheduled.published {
+		return
+	}
+	scheduled.published = doc.Version
+	d.publish(&lsp.PublishDiagnosticsParams{
+		URI:         doc.URI,
+		Version:     &doc.Version,
+		Diagnostics: diagnostics,
+	})
+}
+
+// clear cancels any validation of the document with the given uri,
+// and publishes an empty set of diagnostics for it. The clear has no
+// version, as clients do not send one when closing a document.
+func (d *diagnosticsScheduler) clear(uri lsp.DocumentURI) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if scheduled, ok := d.docs[uri]; ok {
+		d.stop(scheduled)
+		delete(d.docs, uri)
+	}
+	d.publish(&lsp.PublishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: []lsp.Diagnostic{},
+	})
+}
+
+// stop cancels the pending or running validation, if any.
+func (d *diagnosticsScheduler) stop(scheduled *scheduledDiagnostics) {
+	if scheduled.timer != nil && scheduled.timer.Stop() {
+		d.pending.Done()
+	}
+	if scheduled.cancel != nil {
+		scheduled.cancel()
+	}
+}
+
+// wait blocks until all scheduled validations have finished.
+func (d *diagnosticsScheduler) wait() {
+	d.pending.Wait()
+}
+
+// textDocumentDiagnostic returns the diagnostics for a document, or an
+// unchanged report if they are the same as the client's previous result.
+func (s *Server) textDocumentDiagnostic(ctx context.Context, params *lsp.DocumentDiagnosticParams) (any, error) {
+	snap := s.snapshot()
+	doc, err := s.document(snap, params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	full, schemaURI, err := s.diagnosticReport(ctx, snap, doc, params.PreviousResultID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := snap.docs[doc.URI]; !ok {
+		s.recordSchemas(map[lsp.DocumentURI]lsp.DocumentURI{doc.URI: schemaURI})
+	}
+	if full == nil {
+		return &lsp.RelatedUnchangedDocumentDiagnosticReport{
+			UnchangedDocumentDiagnosticReport: lsp.UnchangedDocumentDiagnosticReport{
+				Kind:     string(lsp.DocumentDiagnosticReportKindUnchanged),
+				ResultID: params.PreviousResultID,
+			},
+		}, nil
+	}
+	return &lsp.RelatedFullDocumentDiagnosticReport{FullDocumentDiagnosticReport: *full}, nil
+}
+
+// workspaceDiagnostic returns the diagnostics for every .conl file in the
+// client's workspace folders. Clients send the next request as soon as
+// this one returns, so if every file is unchanged since the client's
+// previous results the request is held open until a document changes.
+func (s *Server) workspaceDiagnostic(ctx context.Context, params *lsp.WorkspaceDiagnosticParams) (*lsp.WorkspaceDiagnosticReport, error) {
+	previous := map[lsp.DocumentURI]string{}
+	for _, p := range params.PreviousResultIDs {
+		previous[p.URI] = p.Value
+	}
+
+	for {
+		s.mutex.RLock()
+		changed := s.changed
+		s.mutex.RUnlock()
+
+		report, unchanged, err := s.workspaceReport(ctx, previous)
+		if err != nil || !unchanged {
+			return report, err
+		}
+		select {
+		case <-changed:
+		case <-s.done:
+			return report, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		// wait for a burst of edits to finish before validating again
+		select {
+		case <-time.After(diagnosticsDelay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// workspaceReport validates every file in the workspace, and reports
+// whether all of them are unchanged since the previous results.
+func (s *Server) workspaceReport(ctx context.Context, previous map[lsp.DocumentURI]string) (*lsp.WorkspaceDiagnosticReport, bool, error) {
+	snap := s.snapshot()
+	report := &lsp.WorkspaceDiagnosticReport{Items: []lsp.WorkspaceDocumentDiagnosticReport{}}
+	s.mutex.RLock()
+	cached := s.fileReports
+	s.mutex.RUnlock()
+	// the schemas used by unopened files are recorded together at the end,
+	// rather than updating the snapshot once per file.
+	schemas := map[lsp.DocumentURI]lsp.DocumentURI{}
+	reports := map[lsp.DocumentURI]*fileReport{}
+	unchanged := true
+	for _, uri := range s.workspaceFiles() {
+		var r *fileReport
+		var version *int32
+		var err error
+		if doc, ok := snap.docs[uri]; ok {
+			r, err = s.diagnose(ctx, snap, doc)
+			version = &doc.Version
+		} else {
+			r, err = s.fileReport(ctx, snap, uri, cached[uri])
+		}
+		if ctx.Err() != nil {
+			return nil, false, ctx.Err()
+		}
+		if err != nil {
+			s.logger.Warn("failed to read document", "uri", uri, "error", err)
+			continue
+		}
+		if version == nil {
+			reports[uri] = r
+			schemas[uri] = r.schema.uri
+		}
+		var item any
+		if full := r.full(previous[uri]); full == nil {
+			item = &lsp.WorkspaceUnchangedDocumentDiagnosticReport{
+				UnchangedDocumentDiagnosticReport: lsp.UnchangedDocumentDiagnosticReport{
+					Kind:     string(lsp.DocumentDiagnosticReportKindUnchanged),
+					ResultID: previous[uri],
+				},
+				URI:     uri,
+				Version: version,
+			}
+		} else {
+			unchanged = false
+			item = &lsp.WorkspaceFullDocumentDiagnosticReport{FullDocumentDiagnosticReport: *full, URI: uri, Version: version}
+		}
+		raw, err := json.Marshal(item)
+		if err != nil {
+			return nil, false, err
+		}
+		report.Items = append(report.Items, raw)
+	}
+	s.mutex.Lock()
+	s.fileReports = reports
+	s.mutex.Unlock()
+	s.recordSchemas(schemas)
+	return report, unchanged, nil
+}
+
+// A fileReport is the diagnostics for a version of a document, identified
+// by a hash of the diagnostics so that clients can skip unchanged results.
+type fileReport struct {
+	version  string
+	schema   schemaKey
+	resultID string
+	items    []lsp.Diagnostic
+}
+
+// full returns r as a full report, or nil if it is the same as the one
+// identified by previousResultID.
+func (r *fileReport) full(previousResultID string) *lsp.FullDocumentDiagnosticReport {
+	if r.resultID == previousResultID {
+		return nil
+	}
+	return &lsp.FullDocumentDiagnosticReport{
+		Kind:     string(lsp.DocumentDiagnosticReportKindFull),
+		ResultID: r.resultID,
+		Items:    r.items,
+	}
+}
+
+// fileReport validates the unopened file at uri, reusing cached if neither
+// the file nor its schema has changed since. Files are versioned in the same
+// way as schemas (see schemaVersion), so that the file is not re-read when
+// all the workspace's diagnostics are pulled after an unrelated edit.
+func (s *Server) fileReport(ctx context.Context, snap *snapshot, uri lsp.DocumentURI, cached *fileReport) (*fileReport, error) {
+	version := s.schemaVersion(snap, uri)
+	if cached != nil && cached.version == version && cached.schema.version == s.schemaVersion(snap, cached.schema.uri) {
+		return cached, nil
+	}
+	doc, err := s.document(snap, uri)
+	if err != nil {
+		return nil, err
+	}
+	r, err := s.diagnose(ctx, snap, doc)
+	if err != nil {
+		return nil, err
+	}
+	r.version = version
+	return r, nil
+}
+
+// diagnosticReport validates doc, and returns nil if the result is the
+// same as the one identified by previousResultID. It also returns the
+// schema that doc uses.
+func (s *Server) diagnosticReport(ctx context.Context, snap *snapshot, doc *TextDocument, previousResultID string) (*lsp.FullDocumentDiagnosticReport, lsp.DocumentURI, error) {
+	r, err := s.diagnose(ctx, snap, doc)
+	if err != nil {
+		return nil, "", err
+	}
+	return r.full(previousResultID), r.schema.uri, nil
+}
+
+// diagnose validates doc against its schema.
+func (s *Server) diagnose(ctx context.Context, snap *snapshot, doc *TextDocument) (*fileReport, error) {
+	a := s.analyze(ctx, snap, doc)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	items := diagnosticsFor(doc, a.result)
+	raw, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	h := fnv.New64a()
+	h.Write(raw)
+	return &fileReport{
+		schema:   a.schema,
+		resultID: fmt.Sprintf("%x", h.Sum64()),
+		items:    items,
+	}, nil
+}
+
+// recordSchemas records the schemas used by unopened documents, so that
+// editing one of them refreshes the workspace's diagnostics.
+func (s *Server) recordSchemas(schemas map[lsp.DocumentURI]lsp.DocumentURI) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	current := s.snapshot().schemasInUse
+	if !slices.ContainsFunc(slices.Collect(maps.Keys(schemas)), func(uri lsp.DocumentURI) bool {
+		return current[uri] != schemas[uri]
+	}) {
+		return
+	}
+	s.update(func(next *snapshot) {
+		for uri, schemaURI := range schemas {
+			if schemaURI == "" {
+				delete(next.schemasInUse, uri)
+			} else {
+				next.schemasInUse[uri] = schemaURI
+			}
+		}
+	})
+}
+
+// documentsChanged wakes any workspace/diagnostic requests that are waiting
+// for a document to change. s.mutex must be held.
+func (s *Server) documentsChanged() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// document returns the document with the given uri from snap if it is open,
+// or reads it from disk.
+func (s *Server) document(snap *snapshot, uri lsp.DocumentURI) (*TextDocument, error) {
+	if doc, ok := snap.docs[uri]; ok {
+		return doc, nil
+	}
+	u := uri.URL()
+	if u.Scheme != "file" {
+		return nil, fmt.Errorf("document %s is not open", uri)
+	}
+	content, err := os.ReadFile(u.Path)
+	if err != nil {
+		return nil, err
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return NewTextDocument(uri, 0, string(content), "conl", s.encoding), nil
+}
+
+// workspaceFiles returns the .conl files in the client's workspace folders
+// (or root), skipping hidden directories.
+func (s *Server) workspaceFiles() []lsp.DocumentURI {
+	s.mutex.RLock()
+	roots := []lsp.DocumentURI{}
+	for _, folder := range s.client.WorkspaceFolders {
+		roots = append(roots, folder.URI)
+	}
+	if len(roots) == 0 && s.client.RootURI != nil {
+		roots = append(roots, *s.client.RootURI)
+	}
+	s.mutex.RUnlock()
+
+	files := []lsp.DocumentURI{}
+	for _, root := range roots {
+		u := root.URL()
+		if u.Scheme != "file" {
+			continue
+		}
+		filepath.WalkDir(u.Path, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if entry.IsDir() && path != u.Path && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			if !entry.IsDir() && filepath.Ext(path) == ".conl" {
+				files = append(files, lsp.DocumentURI((&url.URL{Scheme: "file", Path: path}).String()))
+			}
+			return nil
+		})
+	}
+	return files
+}
+
+// scheduleRefresh asks the client to pull diagnostics again, after the same
+// delay as pushed diagnostics so that typing in a schema causes one refresh.
+// s.mutex must be held.
+func (s *Server) scheduleRefresh() {
+	workspace := s.client.Capabilities.Workspace
+	if workspace == nil || workspace.Diagnostics == nil || !workspace.Diagnostics.RefreshSupport {
+		return
+	}
+	if s.refresh != nil {
+		s.refresh.Reset(diagnosticsDelay)
+		return
+	}
+	s.refresh = time.AfterFunc(diagnosticsDelay, func() {
+		if err := s.c.Request(context.Background(), "workspace/diagnostic/refresh", nil, nil); err != nil {
+			s.logger.Warn("failed to refresh diagnostics", "error", err)
+		}
+	})
+}
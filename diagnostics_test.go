@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ConradIrwin/conl-lsp/lsp"
+)
+
+type publishedLog struct {
+	mutex sync.Mutex
+	// versions has 0 for diagnostics published without a version
+	versions  []int32
+	validated []int32
+}
+
+func newTestScheduler(log *publishedLog, validate func(ctx context.Context, doc *TextDocument)) *diagnosticsScheduler {
+	return newDiagnosticsScheduler(slog.Default(), func(ctx context.Context, doc *TextDocument) []lsp.Diagnostic {
+		log.mutex.Lock()
+		log.validated = append(log.validated, doc.Version)
+		log.mutex.Unlock()
+		validate(ctx, doc)
+		return []lsp.Diagnostic{}
+	}, func(params *lsp.PublishDiagnosticsParams) {
+		log.mutex.Lock()
+		defer log.mutex.Unlock()
+		version := int32(0)
+		if params.Version != nil {
+			version = *params.Version
+		}
+		log.versions = append(log.versions, version)
+	})
+}
+
+func testDoc(version int32) *TextDocument {
+	return NewTextDocument("file:///a.conl", version, "a = b\n", "conl", lsp.PositionEncodingKindUTF16)
+}
+
+func TestDiagnosticsDebounce(t *testing.T) {
+	log := &publishedLog{}
+	d := newTestScheduler(log, func(ctx context.Context, doc *TextDocument) {})
+	for version := range int32(5) {
+		d.schedule(testDoc(version+1), 20*time.Millisecond)
+	}
+	d.wait()
+
+	if !reflect.DeepEqual(log.validated, []int32{5}) || !reflect.DeepEqual(log.versions, []int32{5}) {
+		t.Fatalf("validated %v and published %v, expected only version 5", log.validated, log.versions)
+	}
+}
+
+func TestDiagnosticsCancelSuperseded(t *testing.T) {
+	log := &publishedLog{}
+	started := make(chan struct{})
+	d := newTestScheduler(log, func(ctx context.Context, doc *TextDocument) {
+		if doc.Version == 1 {
+			close(started)
+			<-ctx.Done()
+		}
+	})
+	d.schedule(testDoc(1), 0)
+	<-started
+	d.schedule(testDoc(2), 0)
+	d.wait()
+
+	if !reflect.DeepEqual(log.versions, []int32{2}) {
+		t.Fatalf("published %v, expected only version 2", log.versions)
+	}
+}
+
+func TestDiagnosticsNeverOlder(t *testing.T) {
+	log := &publishedLog{}
+	d := newTestScheduler(log, func(ctx context.Context, doc *TextDocument) {})
+	d.schedule(testDoc(3), 0)
+	d.wait()
+	d.schedule(testDoc(2), 0)
+	d.wait()
+	d.schedule(testDoc(3), 0)
+	d.wait()
+
+	if !reflect.DeepEqual(log.versions, []int32{3, 3}) {
+		t.Fatalf("published %v, expected [3 3]", log.versions)
+	}
+
+	d.clear("file:///a.conl")
+	d.schedule(testDoc(1), 0)
+	d.wait()
+	if !reflect.DeepEqual(log.versions, []int32{3, 3, 0, 1}) {
+		t.Fatalf("published %v, expected a reopened document to start again", log.versions)
+	}
+}
+
+func TestDiagnosticsClear(t *testing.T) {
+	log := &publishedLog{}
+	d := newTestScheduler(log, func(ctx context.Context, doc *TextDocument) {})
+	d.schedule(testDoc(1), time.Hour)
+	d.clear("file:///a.conl")
+	d.wait()
+
+	if !reflect.DeepEqual(log.validated, []int32(nil)) || !reflect.DeepEqual(log.versions, []int32{0}) {
+		t.Fatalf("validated %v and published %v, expected only the clear", log.validated, log.versions)
+	}
+}
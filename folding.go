@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ConradIrwin/conl-lsp/lsp"
+)
+
+func (s *Server) textDocumentFoldingRange(ctx context.Context, params *lsp.FoldingRangeParams) ([]lsp.FoldingRange, error) {
+	doc, ok := s.snapshot().docs[params.TextDocument.URI]
+	if !ok {
+		return nil, fmt.Errorf("document %v not found", params.TextDocument.URI)
+	}
+	return foldingRanges(doc), nil
+}
+
+// foldingRanges returns a range for each key or list item with nested lines
+// (including multiline strings), and for each run of consecutive comment
+// lines. The comments before a schema = line at the start of the document
+// are folded together with it as imports, so that editors can hide them.
+func foldingRanges(doc *TextDocument) []lsp.FoldingRange {
+	lines := parseLines(doc, doc.lineCount())
+	ranges := []lsp.FoldingRange{}
+
+	i := nextContent(lines, 0)
+	if header := schemaHeader(lines); header > i {
+		ranges = append(ranges, lsp.FoldingRange{
+			StartLine: uint32(i),
+			EndLine:   uint32(header),
+			Kind:      lsp.FoldingRangeKindImports,
+		})
+		i = header
+	}
+
+	for ; i >= 0 && i < len(lines); i++ {
+		switch lines[i].kind {
+		case lineCode:
+			if end := blockEnd(lines, i); end > i {
+				ranges = append(ranges, lsp.FoldingRange{StartLine: uint32(i), EndLine: uint32(end)})
+			}
+		case lineComment:
+			end := i
+			for end+1 < len(lines) && lines[end+1].kind == lineComment {
+				end++
+			}
+			if end > i {
+				ranges = append(ranges, lsp.FoldingRange{
+					StartLine: uint32(i),
+					EndLine:   uint32(end),
+					Kind:      lsp.FoldingRangeKindComment,
+				})
+			}
+			i = end
+		}
+	}
+	return ranges
+}
+
+// schemaHeader returns the index of the schema = line if it is the first
+// code line in the document (which may be preceded by comments), or -1.
+func schemaHeader(lines []*formatLine) int {
+	for i, line := range lines {
+		if line.kind == lineBlank || line.kind == lineComment {
+			continue
+		}
+		if key, _, ok := splitAssignment(line.code); line.kind == lineCode && ok && key == "schema" && line.indent == "" {
+			return i
+		}
+		return -1
+	}
+	return -1
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ConradIrwin/conl-lsp/lsp"
+)
+
+func TestFoldingRanges(t *testing.T) {
+	content := `; a service config
+; maintained by the platform team
+schema = ./service.conl
+
+; database settings
+; see the runbook
+database
+  replicas = 3
+  ; comment
+  primary
+    host = db1
+  query = """sql
+    select *
+
+    from t
+
+single = 1
+; trailing
+`
+	doc := NewTextDocument("file:///test.conl", 1, content, "conl", lsp.PositionEncodingKindUTF16)
+	expected := []lsp.FoldingRange{
+		{StartLine: 0, EndLine: 2, Kind: lsp.FoldingRangeKindImports},
+		{StartLine: 4, EndLine: 5, Kind: lsp.FoldingRangeKindComment},
+		{StartLine: 6, EndLine: 14},
+		{StartLine: 9, EndLine: 10},
+		{StartLine: 11, EndLine: 14},
+	}
+	if ranges := foldingRanges(doc); !reflect.DeepEqual(ranges, expected) {
+		t.Fatalf("got %#v, expected %#v", ranges, expected)
+	}
+}
+
+func TestFoldingRangesWithoutHeader(t *testing.T) {
+	doc := NewTextDocument("file:///test.conl", 1, "; one\n; two\na = 1\nschema = x\n", "conl", lsp.PositionEncodingKindUTF16)
+	expected := []lsp.FoldingRange{{StartLine: 0, EndLine: 1, Kind: lsp.FoldingRangeKindComment}}
+	if ranges := foldingRanges(doc); !reflect.DeepEqual(ranges, expected) {
+		t.Fatalf("got %#v, expected %#v", ranges, expected)
+	}
+}
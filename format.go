@@ -0,0 +1,453 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ConradIrwin/conl-go"
+	"github.com/ConradIrwin/conl-lsp/lsp"
+)
+
+// A formatLine is one line of a document being formatted.
+type formatLine struct {
+	kind    formatLineKind
+	indent  string
+	level   int
+	code    string
+	comment string
+	// deleted is set for blank lines that the formatter removes.
+	deleted bool
+}
+
+type formatLineKind int
+
+const (
+	lineBlank formatLineKind = iota
+	lineComment
+	lineCode
+	// lineMultiline is a line (possibly blank) inside a multiline string,
+	// which is re-indented but otherwise left alone.
+	lineMultiline
+)
+
+// textDocumentFormatting re-indents the whole document with the unit requested
+// by the client. Range and on-type formatting instead keep the document's
+// existing unit (see documentIndent), as they change only some lines, which
+// must stay consistent with the lines around them.
+func (s *Server) textDocumentFormatting(ctx context.Context, params *lsp.DocumentFormattingParams) ([]lsp.TextEdit, error) {
+	doc, ok := s.snapshot().docs[params.TextDocument.URI]
+	if !ok {
+		return nil, fmt.Errorf("document %v not found", params.TextDocument.URI)
+	}
+	lines, err := formatDocument(doc, optionsIndent(params.Options))
+	if err != nil {
+		s.logger.Debug("not formatting invalid document", "uri", doc.URI, "error", err)
+		return nil, nil
+	}
+	return formattingEdits(doc, lines), nil
+}
+
+func (s *Server) textDocumentRangeFormatting(ctx context.Context, params *lsp.DocumentRangeFormattingParams) ([]lsp.TextEdit, error) {
+	doc, ok := s.snapshot().docs[params.TextDocument.URI]
+	if !ok {
+		return nil, fmt.Errorf("document %v not found", params.TextDocument.URI)
+	}
+	lines, err := formatDocument(doc, documentIndent(doc, params.Options))
+	if err != nil {
+		s.logger.Debug("not formatting invalid document", "uri", doc.URI, "error", err)
+		return nil, nil
+	}
+	return editsInRange(formattingEdits(doc, lines), params.Range), nil
+}
+
+func (s *Server) textDocumentOnTypeFormatting(ctx context.Context, params *lsp.DocumentOnTypeFormattingParams) ([]lsp.TextEdit, error) {
+	doc, ok := s.snapshot().docs[params.TextDocument.URI]
+	if !ok {
+		return nil, fmt.Errorf("document %v not found", params.TextDocument.URI)
+	}
+	if params.Ch != "\n" {
+		return nil, nil
+	}
+	if int(params.Position.Line) >= doc.lineCount() {
+		return nil, fmt.Errorf("invalid position: %v >= %v", params.Position.Line, doc.lineCount())
+	}
+	return newlineEdits(doc, int(params.Position.Line), documentIndent(doc, params.Options)), nil
+}
+
+// formatDocument returns the canonical formatting of each line of doc.
+// Documents that do not parse are not formatted, as the structure that
+// indentation and spacing depend on is not known.
+// Each level of nesting is indented by unit.
+func formatDocument(doc *TextDocument, unit string) ([]*formatLine, error) {
+	for token := range conl.Tokens([]byte(doc.Content)) {
+		if token.Error != nil {
+			return nil, token.Error
+		}
+	}
+
+	n := doc.lineCount()
+	if strings.HasSuffix(doc.Content, "\n") {
+		n--
+	}
+	lines := parseLines(doc, n)
+	indentLines(lines)
+
+	for i, line := range lines {
+		switch line.kind {
+		case lineComment, lineCode:
+			line.indent = strings.Repeat(unit, line.level)
+		case lineMultiline:
+			line.indent = ""
+			if line.code != "" {
+				line.indent = strings.Repeat(unit, line.level)
+			}
+		case lineBlank:
+			line.deleted = i == 0 || lines[i-1].kind == lineBlank ||
+				opensBlock(lines, i-1) || nextContent(lines, i) == -1
+		}
+	}
+	alignComments(lines)
+	return lines, nil
+}
+
+// parseLines splits each of the first n lines of doc into its indentation,
+// code and trailing comment.
+func parseLines(doc *TextDocument, n int) []*formatLine {
+	lines := make([]*formatLine, n)
+	multiline := -1
+	base := ""
+	for i := range lines {
+		line := &formatLine{}
+		lines[i] = line
+		text := strings.TrimRight(doc.line(i), " \t")
+		code := strings.TrimLeft(text, " \t")
+		line.indent = text[:len(text)-len(code)]
+
+		if multiline >= 0 && (code == "" || len(line.indent) > multiline) {
+			line.kind = lineMultiline
+			if code != "" {
+				if base == "" {
+					base = line.indent
+				}
+				// keep indentation relative to the first line of the string
+				line.code = text[min(len(base), len(line.indent)):]
+			}
+			continue
+		}
+		multiline = -1
+		if code == "" {
+			line.kind = lineBlank
+			continue
+		}
+		if strings.HasPrefix(code, ";") {
+			line.kind = lineComment
+			line.comment = code
+			continue
+		}
+
+		line.kind = lineCode
+		code, line.comment = splitComment(code)
+		line.code = normalizeCode(code)
+		if _, value, ok := splitAssignment(line.code); ok && strings.HasPrefix(value, `"""`) {
+			multiline = len(line.indent)
+			base = ""
+		}
+	}
+
+	// blank lines at the end of a multiline string are not part of it
+	for i := len(lines) - 1; i >= 0; i-- {
+		if lines[i].kind == lineMultiline && lines[i].code == "" && (i+1 == len(lines) || lines[i+1].kind != lineMultiline) {
+			lines[i].kind = lineBlank
+		}
+	}
+	return lines
+}
+
+// indentLines sets the nesting level of each line. Code lines are nested
+// under the closest less indented line. Comments are nested to match their
+// surroundings, and multiline strings are nested under their key.
+func indentLines(lines []*formatLine) {
+	stack := []int{0}
+	levelOf := func(indent string, stack []int) []int {
+		for len(stack) > 1 && stack[len(stack)-1] > len(indent) {
+			stack = stack[:len(stack)-1]
+		}
+		if len(indent) > stack[len(stack)-1] {
+			stack = append(stack, len(indent))
+		}
+		return stack
+	}
+	previous := 0
+	for i, line := range lines {
+		switch line.kind {
+		case lineCode:
+			stack = levelOf(line.indent, stack)
+			line.level = len(stack) - 1
+			previous = line.level
+		case lineMultiline:
+			line.level = previous + 1
+		case lineComment:
+			level := len(levelOf(line.indent, stack[:len(stack):len(stack)])) - 1
+			next := 0
+			if j := nextContent(lines, i+1); j >= 0 {
+				next = len(levelOf(lines[j].indent, stack[:len(stack):len(stack)])) - 1
+			}
+			line.level = min(level, max(previous, next))
+		}
+	}
+}
+
+// optionsIndent returns the indent unit requested by the client.
+func optionsIndent(options lsp.FormattingOptions) string {
+	if options.InsertSpaces {
+		return strings.Repeat(" ", max(int(options.TabSize), 1))
+	}
+	return "\t"
+}
+
+// documentIndent returns the indent unit already used by doc: the
+// indentation added by the first nested line. Documents with no nested
+// lines use the unit requested by the client.
+func documentIndent(doc *TextDocument, options lsp.FormattingOptions) string {
+	previous := ""
+	for _, line := range parseLines(doc, doc.lineCount()) {
+		if line.kind != lineCode {
+			continue
+		}
+		if len(line.indent) > len(previous) && strings.HasPrefix(line.indent, previous) {
+			return line.indent[len(previous):]
+		}
+		previous = line.indent
+	}
+	return optionsIndent(options)
+}
+
+// newlineEdits returns the edit that indents the line lno after a newline
+// has been inserted before it. Lines after a key with no value (or the
+// start of a multiline string) are nested, and other lines keep the
+// indentation of the line before them.
+func newlineEdits(doc *TextDocument, lno int, unit string) []lsp.TextEdit {
+	indent := ""
+	for _, previous := range slices.Backward(parseLines(doc, lno)) {
+		if previous.kind == lineBlank || previous.kind == lineMultiline && previous.code == "" {
+			continue
+		}
+		indent = previous.indent
+		if _, value, _ := splitAssignment(previous.code); previous.kind == lineCode && (value == "" || strings.HasPrefix(value, `"""`)) {
+			indent += unit
+		}
+		break
+	}
+
+	line := doc.line(lno)
+	current := len(line) - len(strings.TrimLeft(line, " \t"))
+	if line[:current] == indent {
+		return []lsp.TextEdit{}
+	}
+	return []lsp.TextEdit{{
+		Range: lsp.Range{
+			Start: lsp.Position{Line: uint32(lno)},
+			End:   lsp.Position{Line: uint32(lno), Character: doc.Encoding.Len(line[:current])},
+		},
+		NewText: indent,
+	}}
+}
+
+// editsInRange returns the edits that start on the lines covered by r.
+func editsInRange(edits []lsp.TextEdit, r lsp.Range) []lsp.TextEdit {
+	last := r.End.Line
+	if r.End.Character == 0 && r.End.Line > r.Start.Line {
+		last--
+	}
+	return slices.DeleteFunc(edits, func(edit lsp.TextEdit) bool {
+		return edit.Range.Start.Line < r.Start.Line || edit.Range.Start.Line > last
+	})
+}
+
+// alignComments lines up the trailing comments of consecutive lines at the
+// same level, one space after the longest line.
+func alignComments(lines []*formatLine) {
+	for i := 0; i < len(lines); {
+		j := i
+		width := 0
+		for j < len(lines) && lines[j].kind == lineCode && lines[j].comment != "" && lines[j].level == lines[i].level {
+			width = max(width, utf8.RuneCountInString(lines[j].code))
+			j++
+		}
+		for _, line := range lines[i:j] {
+			line.code += strings.Repeat(" ", width-utf8.RuneCountInString(line.code))
+		}
+		i = max(j, i+1)
+	}
+}
+
+// opensBlock reports whether the code line at i is followed by nested lines.
+func opensBlock(lines []*formatLine, i int) bool {
+	if lines[i].kind != lineCode {
+		return false
+	}
+	j := nextContent(lines, i+1)
+	return j >= 0 && lines[j].level > lines[i].level
+}
+
+// blockEnd returns the index of the last line nested under the code line at
+// i (including multiline strings), or i if nothing is nested under it.
+// Comments and blank lines after the last nested line are not included.
+func blockEnd(lines []*formatLine, i int) int {
+	last := i
+	for j := i + 1; j < len(lines); j++ {
+		if lines[j].kind == lineCode && len(lines[j].indent) <= len(lines[i].indent) {
+			break
+		}
+		if lines[j].kind == lineCode || lines[j].kind == lineMultiline && lines[j].code != "" {
+			last = j
+		}
+	}
+	return last
+}
+
+// nextContent returns the index of the first non-blank line at or after i, or -1.
+func nextContent(lines []*formatLine, i int) int {
+	for ; i < len(lines); i++ {
+		if lines[i].kind != lineBlank {
+			return i
+		}
+	}
+	return -1
+}
+
+func (line *formatLine) String() string {
+	switch {
+	case line.kind == lineComment:
+		return line.indent + line.comment
+	case line.comment != "":
+		return line.indent + line.code + " " + line.comment
+	}
+	return line.indent + line.code
+}
+
+// splitComment splits a trailing comment (a ; preceded by whitespace,
+// outside of quotes) from code.
+func splitComment(code string) (string, string) {
+	for i := 0; i < len(code); i++ {
+		switch code[i] {
+		case '"':
+			i += quotedLength(code[i:]) - 1
+		case ';':
+			if i > 0 && (code[i-1] == ' ' || code[i-1] == '\t') {
+				return strings.TrimRight(code[:i], " \t"), code[i:]
+			}
+		}
+	}
+	return code, ""
+}
+
+// splitAssignment splits a line at the first = outside of quotes.
+func splitAssignment(code string) (string, string, bool) {
+	for i := 0; i < len(code); i++ {
+		switch code[i] {
+		case '"':
+			i += quotedLength(code[i:]) - 1
+		case '=':
+			return strings.TrimRight(code[:i], " \t"), strings.TrimLeft(code[i+1:], " \t"), true
+		}
+	}
+	return code, "", false
+}
+
+// quotedLength returns the length of the quoted literal at the start of s,
+// or 1 if it is the start of a multiline string or is unterminated.
+func quotedLength(s string) int {
+	if strings.HasPrefix(s, `"""`) {
+		return 1
+	}
+	if match := quotedLiteral.FindString(s); match != "" {
+		return len(match)
+	}
+	return 1
+}
+
+// normalizeCode puts a single space either side of the = in a line.
+func normalizeCode(code string) string {
+	key, value, ok := splitAssignment(code)
+	switch {
+	case !ok:
+		return key
+	case key == "" && value == "":
+		return "="
+	case key == "":
+		return "= " + value
+	case value == "":
+		return key + " ="
+	}
+	return key + " = " + value
+}
+
+// formattingEdits returns the edits that turn doc into the formatted lines.
+// Each changed line gets its own edit covering only the part that changed,
+// so that the client can preserve cursors and markers elsewhere.
+func formattingEdits(doc *TextDocument, lines []*formatLine) []lsp.TextEdit {
+	edits := []lsp.TextEdit{}
+	last := len(lines) - 1
+	for last >= 0 && lines[last].deleted {
+		last--
+	}
+	if last < 0 {
+		if doc.Content == "" {
+			return edits
+		}
+		return []lsp.TextEdit{{Range: lsp.Range{End: doc.unresolve(len(doc.Content))}, NewText: ""}}
+	}
+
+	for i := 0; i <= last; i++ {
+		if lines[i].deleted {
+			j := i
+			for lines[j].deleted {
+				j++
+			}
+			edits = append(edits, lsp.TextEdit{Range: lsp.Range{
+				Start: lsp.Position{Line: uint32(i)},
+				End:   lsp.Position{Line: uint32(j)},
+			}})
+			i = j - 1
+			continue
+		}
+
+		original := doc.line(i)
+		formatted := lines[i].String()
+		if i == last {
+			// the final line is responsible for the rest of the document
+			original = doc.Content[doc.lineStarts[i]:]
+			formatted += "\n"
+		}
+		if original == formatted {
+			continue
+		}
+		start := 0
+		for start < min(len(original), len(formatted)) && original[start] == formatted[start] {
+			start++
+		}
+		for start > 0 && start < len(original) && !utf8.RuneStart(original[start]) {
+			start--
+		}
+		end := 0
+		for end < min(len(original), len(formatted))-start && original[len(original)-1-end] == formatted[len(formatted)-1-end] {
+			end++
+		}
+		for end > 0 && !utf8.RuneStart(original[len(original)-end]) {
+			end--
+		}
+		offset := doc.lineStarts[i]
+		edits = append(edits, lsp.TextEdit{
+			Range: lsp.Range{
+				Start: doc.unresolve(offset + start),
+				End:   doc.unresolve(offset + len(original) - end),
+			},
+			NewText: formatted[start : len(formatted)-end],
+		})
+	}
+	return edits
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ConradIrwin/conl-lsp/lsp"
+)
+
+var testFormattingOptions = lsp.FormattingOptions{TabSize: 2, InsertSpaces: true}
+
+// format returns the edits to format content, and the result of applying them.
+func format(t *testing.T, content string, options lsp.FormattingOptions) ([]lsp.TextEdit, string) {
+	t.Helper()
+	doc := NewTextDocument("file:///test.conl", 1, content, "conl", lsp.PositionEncodingKindUTF16)
+	lines, err := formatDocument(doc, optionsIndent(options))
+	if err != nil {
+		t.Fatal(err)
+	}
+	edits := formattingEdits(doc, lines)
+	for _, edit := range slices.Backward(edits) {
+		if err := doc.applyChange(lsp.TextDocumentContentChangeEvent{Range: &edit.Range, Text: edit.NewText}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return edits, doc.Content
+}
+
+func TestFormatGolden(t *testing.T) {
+	inputs, err := filepath.Glob("testdata/format/*.in")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, input := range inputs {
+		t.Run(filepath.Base(input), func(t *testing.T) {
+			content, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden, err := os.ReadFile(strings.TrimSuffix(input, ".in") + ".golden")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, formatted := format(t, string(content), testFormattingOptions); formatted != string(golden) {
+				t.Fatalf("got:\n%s\nexpected:\n%s", formatted, golden)
+			}
+			if edits, _ := format(t, string(golden), testFormattingOptions); len(edits) != 0 {
+				t.Fatalf("formatting is not idempotent: %v", edits)
+			}
+		})
+	}
+}
+
+func TestFormatMinimalEdits(t *testing.T) {
+	edits, _ := format(t, "a = 1\nb  = 2\nc = 3\n", testFormattingOptions)
+	expected := []lsp.TextEdit{{
+		Range:   lsp.Range{Start: lsp.Position{Line: 1, Character: 2}, End: lsp.Position{Line: 1, Character: 3}},
+		NewText: "",
+	}}
+	if !slices.Equal(edits, expected) {
+		t.Fatalf("got %v, expected %v", edits, expected)
+	}
+}
+
+func TestFormatTabs(t *testing.T) {
+	_, formatted := format(t, "a\n  b\n    c = \"\"\"\n      text\n", lsp.FormattingOptions{TabSize: 4})
+	if expected := "a\n\tb\n\t\tc = \"\"\"\n\t\t\ttext\n"; formatted != expected {
+		t.Fatalf("got %#v, expected %#v", formatted, expected)
+	}
+}
+
+func TestDocumentIndent(t *testing.T) {
+	for content, expected := range map[string]string{
+		"a = 1\n":                      "  ",
+		"a\n    b = 1\n":               "    ",
+		"a\n\tb = 1\n":                 "\t",
+		"a = \"\"\"\n   text\nb\n c\n": " ",
+	} {
+		doc := NewTextDocument("file:///test.conl", 1, content, "conl", lsp.PositionEncodingKindUTF16)
+		if indent := documentIndent(doc, testFormattingOptions); indent != expected {
+			t.Errorf("%#v: got %#v, expected %#v", content, indent, expected)
+		}
+	}
+}
+
+func TestRangeFormatting(t *testing.T) {
+	doc := NewTextDocument("file:///test.conl", 1, "a=1\nb\n    c=2\n    d=3\ne=4\n", "conl", lsp.PositionEncodingKindUTF16)
+	lines, err := formatDocument(doc, documentIndent(doc, testFormattingOptions))
+	if err != nil {
+		t.Fatal(err)
+	}
+	edits := editsInRange(formattingEdits(doc, lines), lsp.Range{
+		Start: lsp.Position{Line: 2},
+		End:   lsp.Position{Line: 4},
+	})
+	expected := []lsp.TextEdit{
+		{Range: lsp.Range{Start: lsp.Position{Line: 2, Character: 5}, End: lsp.Position{Line: 2, Character: 6}}, NewText: " = "},
+		{Range: lsp.Range{Start: lsp.Position{Line: 3, Character: 5}, End: lsp.Position{Line: 3, Character: 6}}, NewText: " = "},
+	}
+	if !slices.Equal(edits, expected) {
+		t.Fatalf("got %v, expected %v", edits, expected)
+	}
+}
+
+func TestFormattingIndentPolicy(t *testing.T) {
+	content := "a\n    b\n        c = 1\n    d = 2\n"
+	// formatting the document adopts the client's unit throughout
+	if _, formatted := format(t, content, testFormattingOptions); formatted != "a\n  b\n    c = 1\n  d = 2\n" {
+		t.Fatalf("got %#v", formatted)
+	}
+
+	// formatting part of it keeps the document's unit, so that the result
+	// still nests correctly under the lines that were not formatted
+	doc := NewTextDocument("file:///test.conl", 1, content, "conl", lsp.PositionEncodingKindUTF16)
+	lines, err := formatDocument(doc, documentIndent(doc, testFormattingOptions))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edits := editsInRange(formattingEdits(doc, lines), lsp.Range{Start: lsp.Position{Line: 2}, End: lsp.Position{Line: 3}}); len(edits) != 0 {
+		t.Fatalf("got %v, expected no edits", edits)
+	}
+	doc = NewTextDocument("file:///test.conl", 2, content+"\n", "conl", lsp.PositionEncodingKindUTF16)
+	if edits := newlineEdits(doc, 4, documentIndent(doc, testFormattingOptions)); len(edits) != 1 || edits[0].NewText != "    " {
+		t.Fatalf("got %v, expected the sibling indentation", edits)
+	}
+}
+
+func TestOnTypeFormatting(t *testing.T) {
+	for _, test := range []struct {
+		content string
+		lno     int
+		indent  string
+	}{
+		{"root\n\n", 1, "  "},
+		{"root\n    \n", 1, "  "},
+		{"a\n  b = 1\n\n", 2, "  "},
+		{"a\n  b = 1\n  ; comment\n\n", 3, "  "},
+		{"a\n  b\n\n", 2, "    "},
+		{"list\n  =\n\n", 2, "    "},
+		{"a\n\tb = \"\"\"\n\n", 2, "\t\t"},
+		{"a = \"\"\"\n    text\n\n", 2, "    "},
+		{"a\n  b = 1\n\n\n", 3, "  "},
+	} {
+		doc := NewTextDocument("file:///test.conl", 1, test.content, "conl", lsp.PositionEncodingKindUTF16)
+		edits := newlineEdits(doc, test.lno, documentIndent(doc, testFormattingOptions))
+		for _, edit := range slices.Backward(edits) {
+			if err := doc.applyChange(lsp.TextDocumentContentChangeEvent{Range: &edit.Range, Text: edit.NewText}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if line := doc.line(test.lno); line != test.indent {
+			t.Errorf("%#v: got %#v, expected %#v", test.content, line, test.indent)
+		}
+	}
+}
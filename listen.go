@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+
+	"github.com/ConradIrwin/conl-lsp/lsp"
+)
+
+// listen accepts clients on the given address until ctx is done.
+// The address is one of tcp://host:port, unix:///path/to/socket or
+// ws://host:port/path, and each client is served by its own Server.
+// allowedOrigins are the web pages (other than ws://host:port itself)
+// that may connect to a ws:// address.
+func listen(ctx context.Context, address string, allowedOrigins []string) error {
+	u, err := url.Parse(address)
+	if err != nil {
+		return fmt.Errorf("invalid listen address %#v: %w", address, err)
+	}
+
+	switch u.Scheme {
+	case "tcp":
+		l, err := net.Listen("tcp", u.Host)
+		if err != nil {
+			return err
+		}
+		return serveListener(ctx, l)
+	case "unix":
+		path := u.Host + u.Path
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		l, err := net.Listen("unix", path)
+		if err != nil {
+			return err
+		}
+		return serveListener(ctx, l)
+	case "ws":
+		l, err := net.Listen("tcp", u.Host)
+		if err != nil {
+			return err
+		}
+		return serveWebSocket(ctx, l, u.Path, allowedOrigins)
+	}
+	return fmt.Errorf("unsupported listen address %#v: expected tcp://, unix:// or ws://", address)
+}
+
+func serveListener(ctx context.Context, l net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go serveClient(ctx, conn)
+	}
+}
+
+// serveClient runs a new Server over the given stream until the client
+// disconnects or sends exit.
+func serveClient(ctx context.Context, conn io.ReadWriteCloser) {
+	defer logPanic(slog.Default())
+	defer conn.Close()
+
+	if err := NewServer(lsp.NewConnection()).Serve(ctx, conn, conn); err != nil {
+		slog.Error("client error", "error", err)
+	}
+}
@@ -3,9 +3,14 @@ package lsp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"reflect"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
 )
 
 type ErrorCode int
@@ -17,24 +22,79 @@ const (
 	EMethodNotFound ErrorCode = -32601
 	EInvalidParams  ErrorCode = -32602
 	EInternalError  ErrorCode = -32603
+
+	EServerNotInitialized ErrorCode = -32002
+	ERequestCancelled     ErrorCode = -32800
 )
 
+// The lifecycle of a connection, as defined by the LSP specification.
+// Before "initialize" only "exit" is accepted, and after "shutdown" only "exit" is accepted.
+type lifecycleState int
+
+const (
+	stateUninitialized lifecycleState = iota
+	stateInitialized
+	stateShutdown
+)
+
+// ErrClosed is returned by Request if the connection closes before a response is received.
+var ErrClosed = errors.New("connection closed")
+
+// maxConcurrentRequests bounds the number of request handlers that run at once.
+// When all workers are busy, further requests wait for one to finish. Messages
+// are still read meanwhile, so that waiting (or running) requests can be
+// cancelled, and responses to Request are received.
+const maxConcurrentRequests = 16
+
 type handler struct {
 	notification func(ctx context.Context, val any)
 	request      func(ctx context.Context, val any) (any, error)
 	pType        reflect.Type
 }
 
+// A Connection dispatches incoming JSON RPC messages to registered handlers.
+//
+// Notifications are handled one at a time in the order they arrive, so
+// that (for example) document edits are applied consistently. Requests are
+// handled concurrently by a bounded pool of workers; any notification that
+// precedes a request will have been handled before the request starts.
 type Connection struct {
-	handlers map[string]handler
-	out      chan *Frame
-	cancel   context.CancelFunc
+	handlers   map[string]handler
+	middleware []Middleware
+	logger     *slog.Logger
+	outbox     *outbox
+	out        chan *Frame
+	closed     chan struct{}
+	cancel     context.CancelFunc
+	workers    chan struct{}
+	wg         sync.WaitGroup
+
+	showPanic sync.Once
+
+	mutex    sync.Mutex
+	state    lifecycleState
+	trace    TraceValues
+	recorder *recorder
+	inflight map[string]context.CancelFunc
+	pending  map[string]chan *Frame
+	nextId   atomic.Int64
 }
 
 func NewConnection() *Connection {
-	return &Connection{
+	c := &Connection{
 		handlers: make(map[string]handler),
+		outbox:   newOutbox(),
+		out:      make(chan *Frame),
+		closed:   make(chan struct{}),
+		inflight: make(map[string]context.CancelFunc),
+		pending:  make(map[string]chan *Frame),
+		workers:  make(chan struct{}, maxConcurrentRequests),
+		trace:    TraceValuesOff,
 	}
+	c.logger = slog.New(&clientLogHandler{handler: slog.Default().Handler(), c: c})
+	HandleNotification(c, "$/cancelRequest", c.cancelRequest)
+	HandleNotification(c, "$/setTrace", c.setTrace)
+	return c
 }
 
 func HandleNotification[T any](c *Connection, method string, fn func(ctx context.Context, val T)) {
@@ -58,33 +118,68 @@ func HandleRequest[T any, U any](c *Connection, method string, fn func(ctx conte
 func (c *Connection) Serve(ctx context.Context, in io.Reader, out io.WriteCloser) error {
 	errCh := make(chan error, 1)
 	ctx, cancel := context.WithCancel(ctx)
-	c.out = make(chan *Frame)
 	c.cancel = cancel
 	defer cancel()
 
+	go func() {
+		<-ctx.Done()
+		close(c.closed)
+	}()
+	go c.drainOutbox()
 	go func() {
 		if err := WriteFrames(ctx, out, c.out); err != nil {
-			FrameLogger("output error", []byte(err.Error()))
+			c.logger.Error("output error", "error", err)
 			errCh <- err
+			cancel()
 		}
 		out.Close()
 		close(errCh)
 	}()
 
+	// requests are cancelled as soon as there is no more input, as no
+	// further responses or cancellations can arrive from the client.
+	requests, cancelRequests := context.WithCancel(ctx)
+	defer cancelRequests()
+
+read:
 	for msg, err := range ReadFrames(in) {
 		if err != nil {
-			FrameLogger("input error", []byte(err.Error()))
+			c.logger.Error("input error", "error", err)
 			break
 		}
-		c.handleFrame(ctx, msg)
+		c.record("recv", msg)
+		c.traceFrame("recv", msg)
+		c.handleFrame(requests, msg)
 		select {
 		case <-ctx.Done():
-			break
+			break read
 		default:
 		}
 	}
+	cancelRequests()
+	c.wg.Wait()
+	c.outbox.close()
+	err := <-errCh
 	cancel()
-	return <-errCh
+	return err
+}
+
+// drainOutbox passes queued frames to the writer until the outbox
+// is closed and empty, or the connection is closed.
+func (c *Connection) drainOutbox() {
+	defer close(c.out)
+	for {
+		frame, ok := c.outbox.pop(c.closed)
+		if !ok {
+			return
+		}
+		select {
+		case c.out <- frame:
+			c.record("send", frame)
+		case <-c.closed:
+			return
+		}
+	}
 }
 
 func (c *Connection) Notify(method string, params any) {
@@ -92,72 +187,318 @@ func (c *Connection) Notify(method string, params any) {
 	if err != nil {
 		panic(err)
 	}
-	c.out <- &Frame{
+	c.send(&Frame{
+		JsonRPC: "2.0",
+		Method:  method,
+		Params:  json.RawMessage(raw),
+	})
+}
+
+// Request sends a request to the client and waits for the response,
+// which is unmarshalled into result (unless result is nil).
+//
+// If ctx is done before the client responds, a $/cancelRequest is sent to the
+// client and ctx.Err() is returned. If the client responds with an error,
+// it is returned as an *RpcError.
+//
+// Responses are read by the same goroutine that runs notification handlers,
+// so Request must only be called from request handlers or other goroutines.
+// Reading does not wait for a free worker, so every worker may be blocked in
+// Request at once.
+func (c *Connection) Request(ctx context.Context, method string, params any, result any) error {
+	id, err := json.Marshal(c.nextId.Add(1))
+	if err != nil {
+		panic(err)
+	}
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	ch := make(chan *Frame, 1)
+	c.mutex.Lock()
+	c.pending[string(id)] = ch
+	c.mutex.Unlock()
+	defer func() {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		delete(c.pending, string(id))
+	}()
+
+	c.send(&Frame{
 		JsonRPC: "2.0",
+		Id:      id,
 		Method:  method,
 		Params:  json.RawMessage(raw),
+	})
+
+	select {
+	case resp := <-ch:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(resp.Result, result)
+	case <-ctx.Done():
+		c.Notify("$/cancelRequest", &CancelParams{ID: id})
+		return ctx.Err()
+	case <-c.closed:
+		return ErrClosed
 	}
 }
 
+// send queues a frame for writing, or drops it if the connection is closed.
+// If the queue is full, send blocks until the client has caught up.
+func (c *Connection) send(frame *Frame) {
+	c.traceFrame("send", frame)
+	c.outbox.push(frame, c.closed)
+}
+
 // Terminate the connection
 func (c *Connection) Exit() {
 	c.cancel()
 }
 
+// ShutdownRequested returns true if the client sent a shutdown request.
+// Per the specification, the server should exit with status 0 if this is true
+// and with status 1 otherwise.
+func (c *Connection) ShutdownRequested() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.state == stateShutdown
+}
+
+// advanceLifecycle checks whether method may be called in the current state,
+// and records any state transition that it causes.
+func (c *Connection) advanceLifecycle(method string) *RpcError {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	switch {
+	case method == "exit":
+		return nil
+	case c.state == stateUninitialized:
+		if method == "initialize" {
+			c.state = stateInitialized
+			return nil
+		}
+		return &RpcError{Code: EServerNotInitialized, Message: "server not initialized"}
+	case c.state == stateShutdown:
+		return &RpcError{Code: EInvalidRequest, Message: "server is shutting down"}
+	case method == "initialize":
+		return &RpcError{Code: EInvalidRequest, Message: "server already initialized"}
+	case method == "shutdown":
+		c.state = stateShutdown
+	}
+	return nil
+}
+
 func (c *Connection) handleFrame(ctx context.Context, recv *Frame) {
 	if recv.Batch != nil {
-		c.respondError(json.RawMessage(nil), EParseError, fmt.Errorf("batch requests are not yet supported"))
+		c.handleBatch(ctx, recv.Batch)
+		return
+	}
+	c.dispatch(ctx, recv, c.send, &c.wg)
+}
+
+// handleBatch dispatches each frame in a batch, and once they have all
+// completed sends the responses back to the client as a single batch.
+// As required by JSON RPC, no response is sent if the batch contained
+// only notifications.
+func (c *Connection) handleBatch(ctx context.Context, batch []*Frame) {
+	if len(batch) == 0 {
+		c.send(errorFrame(nil, EInvalidRequest, fmt.Errorf("batch cannot be empty")))
 		return
 	}
 
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	responses := []*Frame{}
+	reply := func(frame *Frame) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		responses = append(responses, frame)
+	}
+	for _, recv := range batch {
+		c.dispatch(ctx, recv, reply, &wg)
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		wg.Wait()
+		if len(responses) > 0 {
+			c.send(&Frame{Batch: responses})
+		}
+	}()
+}
+
+// dispatch handles a single frame, calling reply with the response (if any).
+// Notifications are handled synchronously, requests are handled on a
+// goroutine (tracked by wg) once a worker is free.
+func (c *Connection) dispatch(ctx context.Context, recv *Frame, reply func(*Frame), wg *sync.WaitGroup) {
+	if recv.invalid != nil {
+		reply(errorFrame(nil, EInvalidRequest, recv.invalid))
+		return
+	}
 	msgId := recv.Id
+	if recv.Method == "" && msgId != nil {
+		c.handleResponse(recv)
+		return
+	}
+	if err := c.advanceLifecycle(recv.Method); err != nil {
+		if msgId != nil {
+			reply(&Frame{JsonRPC: "2.0", Error: err, Id: msgId})
+		}
+		return
+	}
 	handler, ok := c.handlers[recv.Method]
 	if !ok {
 		if msgId != nil {
-			c.respondError(msgId, EMethodNotFound, fmt.Errorf("%s not found", recv.Method))
+			reply(errorFrame(msgId, EMethodNotFound, fmt.Errorf("%s not found", recv.Method)))
 		}
 		return
 	}
 
 	param := reflect.New(handler.pType)
-	if err := json.Unmarshal(recv.Params, param.Interface()); err != nil {
-		c.respondError(msgId, EInvalidParams, err)
-		return
+	if handler.pType.Kind() == reflect.Pointer {
+		param.Elem().Set(reflect.New(handler.pType.Elem()))
+	}
+	if len(recv.Params) > 0 && string(recv.Params) != "null" {
+		if err := json.Unmarshal(recv.Params, param.Interface()); err != nil {
+			// notifications cannot be answered, even with an error
+			if msgId == nil {
+				c.logger.Warn("invalid params", "method", recv.Method, "error", err)
+			} else {
+				reply(errorFrame(msgId, EInvalidParams, err))
+			}
+			return
+		}
 	}
 
 	if handler.notification != nil {
 		if recv.Id != nil {
-			c.respondError(msgId, EInvalidRequest, fmt.Errorf("notification cannot have an 'id'"))
+			reply(errorFrame(msgId, EInvalidRequest, fmt.Errorf("notification cannot have an 'id'")))
 		}
-		handler.notification(ctx, param.Elem().Interface())
+		c.handleNotification(ctx, handler, &Call{Method: recv.Method, Params: param.Elem().Interface()})
 		return
 	}
 
 	if len(recv.Id) == 0 {
 		return
 	}
-	result, err := handler.request(ctx, param.Elem().Interface())
+	ctx, cancel := c.startRequest(ctx, msgId)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer c.finishRequest(msgId, cancel)
+		// a request that arrived before the input ended still runs if a
+		// worker is free, rather than racing its cancellation.
+		select {
+		case c.workers <- struct{}{}:
+		default:
+			select {
+			case c.workers <- struct{}{}:
+			case <-ctx.Done():
+				reply(errorFrame(msgId, ERequestCancelled, ctx.Err()))
+				return
+			}
+		}
+		defer func() { <-c.workers }()
+		reply(c.handleRequest(ctx, handler, &Call{Method: recv.Method, ID: msgId, Params: param.Elem().Interface()}))
+	}()
+}
+
+func (c *Connection) handleNotification(ctx context.Context, handler handler, call *Call) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.handlePanic(call.Method, r)
+		}
+	}()
+	c.wrap(handler)(ctx, call)
+}
+
+func (c *Connection) handleRequest(ctx context.Context, handler handler, call *Call) (frame *Frame) {
+	defer func() {
+		if r := recover(); r != nil {
+			frame = errorFrame(call.ID, EInternalError, c.handlePanic(call.Method, r))
+		}
+	}()
+
+	result, err := c.wrap(handler)(ctx, call)
 	if err != nil {
-		c.respondError(msgId, EInternalError, err)
-		return
+		if ctx.Err() != nil {
+			return errorFrame(call.ID, ERequestCancelled, err)
+		}
+		return errorFrame(call.ID, EInternalError, err)
+	}
+	return resultFrame(call.ID, result)
+}
+
+// handlePanic logs a panic from the handler for method, and the first time
+// it happens tells the user. It returns a short error to send to the client.
+func (c *Connection) handlePanic(method string, r any) error {
+	c.logger.Error("panic", "method", method, "error", fmt.Sprint(r), "stack", string(debug.Stack()))
+	c.showPanic.Do(func() {
+		c.Notify("window/showMessage", &ShowMessageParams{
+			Type:    MessageTypeError,
+			Message: fmt.Sprintf("Internal error while handling %s. See the log for details.", method),
+		})
+	})
+	return fmt.Errorf("internal error: %v", r)
+}
+
+// startRequest derives a context for the request with the given id
+// that is cancelled if the client sends a $/cancelRequest for it.
+func (c *Connection) startRequest(ctx context.Context, id json.RawMessage) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.inflight[string(id)] = cancel
+	return ctx, cancel
+}
+
+func (c *Connection) finishRequest(id json.RawMessage, cancel context.CancelFunc) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.inflight, string(id))
+	cancel()
+}
+
+// handleResponse passes a response from the client to the waiting call to Request.
+func (c *Connection) handleResponse(recv *Frame) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if ch, ok := c.pending[string(recv.Id)]; ok {
+		ch <- recv
+		delete(c.pending, string(recv.Id))
+	}
+}
+
+func (c *Connection) cancelRequest(ctx context.Context, params *CancelParams) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if cancel, ok := c.inflight[string(params.ID)]; ok {
+		cancel()
 	}
-	c.respond(msgId, result)
 }
 
-func (c *Connection) respond(id json.RawMessage, result any) {
+func resultFrame(id json.RawMessage, result any) *Frame {
 	raw, err := json.Marshal(result)
 	if err != nil {
 		panic(err)
 	}
-	c.out <- &Frame{
+	return &Frame{
 		JsonRPC: "2.0",
 		Result:  json.RawMessage(raw),
 		Id:      id,
 	}
 }
 
-func (c *Connection) respondError(id json.RawMessage, code ErrorCode, err error) {
-	c.out <- &Frame{
+func errorFrame(id json.RawMessage, code ErrorCode, err error) *Frame {
+	return &Frame{
 		JsonRPC: "2.0",
 		Error: &RpcError{
 			Code:    code,
@@ -0,0 +1,563 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testClient struct {
+	readFrame func() (*Frame, error, bool)
+	writer    chan *Frame
+	in        io.Writer
+	t         *testing.T
+}
+
+// newTestClient serves c over a pipe, and completes the initialize handshake
+func newTestClient(t *testing.T, c *Connection) *testClient {
+	if _, ok := c.handlers["initialize"]; !ok {
+		HandleRequest(c, "initialize", func(ctx context.Context, params *Null) (*Null, error) {
+			return &Null{}, nil
+		})
+	}
+	readIn, writeIn := io.Pipe()
+	readOut, writeOut := io.Pipe()
+
+	go func() {
+		if err := c.Serve(context.Background(), readIn, writeOut); err != nil {
+			panic(err)
+		}
+	}()
+
+	readFrame, stop := iter.Pull2(ReadFrames(readOut))
+	ch := make(chan *Frame)
+	t.Cleanup(func() {
+		writeIn.Close()
+		stop()
+	})
+
+	go func() {
+		if err := WriteFrames(t.Context(), writeIn, ch); err != nil {
+			panic(err)
+		}
+	}()
+
+	client := &testClient{readFrame: readFrame, writer: ch, in: writeIn, t: t}
+	client.send(-1, "initialize", nil)
+	client.recv()
+	return client
+}
+
+func (tc *testClient) send(id int, method string, params any) {
+	tc.t.Helper()
+	raw, err := json.Marshal(params)
+	if err != nil {
+		tc.t.Fatal(err)
+	}
+	frame := &Frame{JsonRPC: "2.0", Method: method, Params: json.RawMessage(raw)}
+	if id != 0 {
+		frame.Id, _ = json.Marshal(id)
+	}
+	tc.writer <- frame
+}
+
+// sendRaw sends msg as is, for messages that a Frame cannot represent.
+func (tc *testClient) sendRaw(msg string) {
+	fmt.Fprintf(tc.in, "Content-Length: %d\r\n\r\n%s", len(msg), msg)
+}
+
+func (tc *testClient) recv() *Frame {
+	tc.t.Helper()
+	ch := make(chan *Frame)
+	go func() {
+		frame, err, ok := tc.readFrame()
+		if !ok || err != nil {
+			close(ch)
+			return
+		}
+		ch <- frame
+	}()
+	select {
+	case frame, ok := <-ch:
+		if !ok {
+			tc.t.Fatal("connection closed")
+		}
+		return frame
+	case <-time.After(time.Second):
+		tc.t.Fatal("timeout")
+	}
+	return nil
+}
+
+func expectResult(t *testing.T, frame *Frame, id int, expected any) {
+	t.Helper()
+	if frame.Error != nil {
+		t.Fatalf("got error %#v, expected result", frame.Error)
+	}
+	if string(frame.Id) != string(mustMarshal(id)) {
+		t.Fatalf("got response to %s, expected %d", frame.Id, id)
+	}
+	actual := reflect.New(reflect.TypeOf(expected))
+	if err := json.Unmarshal(frame.Result, actual.Interface()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual.Elem().Interface(), expected) {
+		t.Fatalf("got %#v, expected %#v", actual.Elem().Interface(), expected)
+	}
+}
+
+func mustMarshal(v any) json.RawMessage {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return raw
+}
+
+type orderedLog struct {
+	mutex sync.Mutex
+	items []string
+}
+
+func (l *orderedLog) append(ctx context.Context, item string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.items = append(l.items, item)
+}
+
+func (l *orderedLog) get(ctx context.Context, params *Null) ([]string, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return append([]string{}, l.items...), nil
+}
+
+func TestConcurrentRequests(t *testing.T) {
+	c := NewConnection()
+	release := make(chan struct{})
+	log := &orderedLog{}
+	HandleRequest(c, "test/block", func(ctx context.Context, params *Null) (string, error) {
+		<-release
+		return "released", nil
+	})
+	HandleNotification(c, "test/append", log.append)
+	HandleRequest(c, "test/log", log.get)
+	client := newTestClient(t, c)
+
+	client.send(1, "test/block", nil)
+	client.send(0, "test/append", "a")
+	client.send(0, "test/append", "b")
+	client.send(2, "test/log", nil)
+	expectResult(t, client.recv(), 2, []string{"a", "b"})
+
+	client.send(0, "test/append", "c")
+	client.send(3, "test/log", nil)
+	expectResult(t, client.recv(), 3, []string{"a", "b", "c"})
+
+	close(release)
+	expectResult(t, client.recv(), 1, "released")
+}
+
+func TestCancelRequest(t *testing.T) {
+	c := NewConnection()
+	HandleRequest(c, "test/block", func(ctx context.Context, params *Null) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	client := newTestClient(t, c)
+
+	client.send(1, "test/block", nil)
+	client.send(0, "$/cancelRequest", CancelParams{ID: mustMarshal(1)})
+
+	frame := client.recv()
+	if frame.Error == nil || frame.Error.Code != ERequestCancelled {
+		t.Fatalf("got %#v, expected RequestCancelled", frame)
+	}
+}
+
+func TestCancelWaitingRequest(t *testing.T) {
+	c := NewConnection()
+	release := make(chan struct{})
+	// any of the requests may be the one left waiting for a worker, so
+	// the handler must also stop when cancelled.
+	HandleRequest(c, "test/block", func(ctx context.Context, params *Null) (string, error) {
+		select {
+		case <-release:
+			return "done", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	})
+	client := newTestClient(t, c)
+
+	for i := range maxConcurrentRequests + 1 {
+		client.send(i+1, "test/block", nil)
+	}
+	client.send(0, "$/cancelRequest", CancelParams{ID: mustMarshal(maxConcurrentRequests + 1)})
+
+	frame := client.recv()
+	if string(frame.Id) != fmt.Sprint(maxConcurrentRequests+1) || frame.Error == nil || frame.Error.Code != ERequestCancelled {
+		t.Fatalf("got %#v, expected the waiting request to be cancelled", frame)
+	}
+	close(release)
+	for range maxConcurrentRequests {
+		if frame := client.recv(); frame.Error != nil {
+			t.Fatalf("got %#v, expected a result", frame)
+		}
+	}
+}
+
+func TestBatch(t *testing.T) {
+	c := NewConnection()
+	log := &orderedLog{}
+	HandleNotification(c, "test/append", log.append)
+	HandleRequest(c, "test/log", log.get)
+	client := newTestClient(t, c)
+
+	client.writer <- &Frame{Batch: []*Frame{
+		{JsonRPC: "2.0", Method: "test/append", Params: mustMarshal("a")},
+		{JsonRPC: "2.0", Id: mustMarshal(1), Method: "test/log"},
+		{JsonRPC: "2.0", Id: mustMarshal(2), Method: "test/missing"},
+	}}
+
+	frame := client.recv()
+	if len(frame.Batch) != 2 {
+		t.Fatalf("got %#v, expected a batch of two responses", frame)
+	}
+	for _, resp := range frame.Batch {
+		switch string(resp.Id) {
+		case "1":
+			expectResult(t, resp, 1, []string{"a"})
+		case "2":
+			if resp.Error == nil || resp.Error.Code != EMethodNotFound {
+				t.Fatalf("got %#v, expected MethodNotFound", resp)
+			}
+		default:
+			t.Fatalf("unexpected response %#v", resp)
+		}
+	}
+
+	client.writer <- &Frame{Batch: []*Frame{
+		{JsonRPC: "2.0", Method: "test/append", Params: mustMarshal("b")},
+	}}
+	client.send(3, "test/log", nil)
+	expectResult(t, client.recv(), 3, []string{"a", "b"})
+}
+
+func TestBatchWithInvalidElements(t *testing.T) {
+	c := NewConnection()
+	log := &orderedLog{}
+	HandleNotification(c, "test/append", log.append)
+	HandleRequest(c, "test/log", log.get)
+	client := newTestClient(t, c)
+
+	client.sendRaw(`[1, {"jsonrpc":"2.0","method":5}, {"jsonrpc":"2.0","method":"test/append","params":5}, ` +
+		`{"jsonrpc":"2.0","method":"test/append","params":"a"}, {"jsonrpc":"2.0","id":1,"method":"test/log"}]`)
+
+	// the notification with invalid params is logged, not answered
+	if frame := client.recv(); frame.Method != "window/logMessage" {
+		t.Fatalf("got %#v, expected a warning", frame)
+	}
+	frame := client.recv()
+	if len(frame.Batch) != 3 {
+		t.Fatalf("got %#v, expected a batch of three responses", frame)
+	}
+	for _, resp := range frame.Batch {
+		if string(resp.Id) == "1" {
+			expectResult(t, resp, 1, []string{"a"})
+		} else if resp.Id != nil || resp.Error == nil || resp.Error.Code != EInvalidRequest {
+			t.Fatalf("got %#v, expected InvalidRequest", resp)
+		}
+	}
+}
+
+func TestServerRequest(t *testing.T) {
+	c := NewConnection()
+	HandleRequest(c, "test/ask", func(ctx context.Context, params *Null) (string, error) {
+		var answer string
+		if err := c.Request(ctx, "client/answer", "question", &answer); err != nil {
+			return "", err
+		}
+		return "client said " + answer, nil
+	})
+	client := newTestClient(t, c)
+
+	client.send(1, "test/ask", nil)
+	req := client.recv()
+	if req.Method != "client/answer" || string(req.Params) != `"question"` {
+		t.Fatalf("got %#v, expected client/answer request", req)
+	}
+	client.writer <- &Frame{JsonRPC: "2.0", Id: req.Id, Result: mustMarshal("yes")}
+	expectResult(t, client.recv(), 1, "client said yes")
+
+	client.send(2, "test/ask", nil)
+	req = client.recv()
+	client.writer <- &Frame{JsonRPC: "2.0", Id: req.Id, Error: &RpcError{Code: EInvalidRequest, Message: "no"}}
+	frame := client.recv()
+	if frame.Error == nil || frame.Error.Message != "no (code -32600)" {
+		t.Fatalf("got %#v, expected client error to be returned", frame)
+	}
+}
+
+func TestServerRequestFromEveryWorker(t *testing.T) {
+	c := NewConnection()
+	HandleRequest(c, "test/ask", func(ctx context.Context, params *Null) (string, error) {
+		return "", c.Request(ctx, "client/answer", nil, nil)
+	})
+	client := newTestClient(t, c)
+
+	// with every worker waiting in Request, responses must still be read
+	for i := range maxConcurrentRequests + 1 {
+		client.send(i+1, "test/ask", nil)
+	}
+	for results := 0; results < maxConcurrentRequests+1; {
+		frame := client.recv()
+		switch {
+		case frame.Method == "client/answer":
+			client.writer <- &Frame{JsonRPC: "2.0", Id: frame.Id, Result: mustMarshal(nil)}
+		case frame.Error != nil:
+			t.Fatalf("got %#v, expected a result", frame)
+		default:
+			results++
+		}
+	}
+}
+
+func TestServerRequestTimeout(t *testing.T) {
+	c := NewConnection()
+	HandleRequest(c, "test/ask", func(ctx context.Context, params *Null) (string, error) {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		return "", c.Request(ctx, "client/answer", nil, nil)
+	})
+	client := newTestClient(t, c)
+
+	client.send(1, "test/ask", nil)
+	req := client.recv()
+	cancel := client.recv()
+	if cancel.Method != "$/cancelRequest" || string(cancel.Params) != `{"id":`+string(req.Id)+`}` {
+		t.Fatalf("got %#v, expected $/cancelRequest for %s", cancel, req.Id)
+	}
+	if frame := client.recv(); frame.Error == nil {
+		t.Fatalf("got %#v, expected timeout error", frame)
+	}
+}
+
+func TestEOFCancelsRequests(t *testing.T) {
+	c := NewConnection()
+	HandleRequest(c, "wait", func(ctx context.Context, params *Null) (*Null, error) {
+		return nil, c.Request(ctx, "client/wait", nil, nil)
+	})
+	readIn, writeIn := io.Pipe()
+	readOut, writeOut := io.Pipe()
+	go io.Copy(io.Discard, readOut)
+
+	done := make(chan error)
+	go func() {
+		done <- c.Serve(context.Background(), readIn, writeOut)
+	}()
+	for _, msg := range []string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"wait"}`,
+	} {
+		fmt.Fprintf(writeIn, "Content-Length: %d\r\n\r\n%s", len(msg), msg)
+	}
+	writeIn.Close()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Serve did not return after the input closed")
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	c := NewConnection()
+	HandleRequest(c, "initialize", func(ctx context.Context, params *Null) (*Null, error) {
+		return &Null{}, nil
+	})
+	HandleRequest(c, "shutdown", func(ctx context.Context, params *Null) (*Null, error) {
+		return &Null{}, nil
+	})
+	HandleNotification(c, "exit", func(ctx context.Context, params *Null) {
+		c.Exit()
+	})
+	log := &orderedLog{}
+	HandleNotification(c, "test/append", log.append)
+	HandleRequest(c, "test/log", log.get)
+
+	readIn, writeIn := io.Pipe()
+	readOut, writeOut := io.Pipe()
+	done := make(chan error)
+	go func() {
+		done <- c.Serve(context.Background(), readIn, writeOut)
+	}()
+	readFrame, stop := iter.Pull2(ReadFrames(readOut))
+	defer stop()
+	ch := make(chan *Frame)
+	go WriteFrames(t.Context(), writeIn, ch)
+	client := &testClient{readFrame: readFrame, writer: ch, t: t}
+
+	expectError := func(code ErrorCode) {
+		t.Helper()
+		frame := client.recv()
+		if frame.Error == nil || frame.Error.Code != code {
+			t.Fatalf("got %#v, expected error %d", frame, code)
+		}
+	}
+
+	client.send(0, "test/append", "ignored")
+	client.send(1, "test/log", nil)
+	expectError(EServerNotInitialized)
+
+	client.send(2, "initialize", nil)
+	client.recv()
+	client.send(3, "initialize", nil)
+	expectError(EInvalidRequest)
+
+	client.send(0, "test/append", "a")
+	client.send(4, "shutdown", nil)
+	client.recv()
+	client.send(0, "test/append", "ignored")
+	client.send(5, "test/log", nil)
+	expectError(EInvalidRequest)
+
+	client.send(0, "exit", nil)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+	if !c.ShutdownRequested() {
+		t.Fatal("expected shutdown to be recorded")
+	}
+	if !reflect.DeepEqual(log.items, []string{"a"}) {
+		t.Fatalf("got %#v, expected only notifications between initialize and shutdown", log.items)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	c := NewConnection()
+	log := &orderedLog{}
+	HandleRequest(c, "test/log", log.get)
+	client := newTestClient(t, c)
+
+	client.send(0, "$/setTrace", SetTraceParams{Value: TraceValuesMessages})
+	client.send(1, "test/log", nil)
+
+	for _, expected := range []string{"Received request 'test/log - (1)'", "Sending response '(1)'"} {
+		frame := client.recv()
+		params := LogTraceParams{}
+		if err := json.Unmarshal(frame.Params, &params); err != nil {
+			t.Fatal(err)
+		}
+		if frame.Method != "$/logTrace" || params.Message != expected {
+			t.Fatalf("got %#v, expected $/logTrace %#v", frame, expected)
+		}
+	}
+	expectResult(t, client.recv(), 1, []string{})
+}
+
+func TestLogMessage(t *testing.T) {
+	c := NewConnection()
+	HandleRequest(c, "test/warn", func(ctx context.Context, params *Null) (*Null, error) {
+		c.Logger().Debug("not sent to the client")
+		c.Logger().With("uri", "a.conl").Warn("invalid change", "line", 3)
+		return &Null{}, nil
+	})
+	client := newTestClient(t, c)
+
+	client.send(1, "test/warn", nil)
+	frame := client.recv()
+	params := LogMessageParams{}
+	if err := json.Unmarshal(frame.Params, &params); err != nil {
+		t.Fatal(err)
+	}
+	expected := LogMessageParams{Type: MessageTypeWarning, Message: "invalid change uri=a.conl line=3"}
+	if frame.Method != "window/logMessage" || params != expected {
+		t.Fatalf("got %#v %#v, expected window/logMessage %#v", frame, params, expected)
+	}
+	expectResult(t, client.recv(), 1, (*Null)(nil))
+}
+
+func TestPanic(t *testing.T) {
+	c := NewConnection()
+	HandleRequest(c, "test/panicRequest", func(ctx context.Context, params *Null) (*Null, error) {
+		panic("oops")
+	})
+	HandleNotification(c, "test/panicNotification", func(ctx context.Context, params *Null) {
+		panic("oops")
+	})
+	log := &orderedLog{}
+	HandleRequest(c, "test/log", log.get)
+	client := newTestClient(t, c)
+
+	recvIgnoringLogs := func() *Frame {
+		t.Helper()
+		for {
+			frame := client.recv()
+			if frame.Method != "window/logMessage" {
+				return frame
+			}
+		}
+	}
+
+	client.send(1, "test/panicRequest", nil)
+	if frame := recvIgnoringLogs(); frame.Method != "window/showMessage" {
+		t.Fatalf("got %#v, expected window/showMessage", frame)
+	}
+	frame := recvIgnoringLogs()
+	if frame.Error == nil || frame.Error.Code != EInternalError || frame.Error.Message != "internal error: oops" {
+		t.Fatalf("got %#v, expected InternalError", frame)
+	}
+
+	client.send(0, "test/panicNotification", nil)
+	client.send(2, "test/log", nil)
+	expectResult(t, recvIgnoringLogs(), 2, []string{})
+}
+
+func TestMiddleware(t *testing.T) {
+	c := NewConnection()
+	log := &orderedLog{}
+	HandleNotification(c, "test/append", log.append)
+	HandleRequest(c, "test/log", log.get)
+	calls := &orderedLog{}
+	for _, name := range []string{"outer", "inner"} {
+		c.Use(func(next Handler) Handler {
+			return func(ctx context.Context, call *Call) (any, error) {
+				calls.append(ctx, fmt.Sprintf("%s %s %s", name, call.Method, string(call.ID)))
+				result, err := next(ctx, call)
+				if name == "inner" && call.Method == "test/log" {
+					return append(result.([]string), "intercepted"), err
+				}
+				return result, err
+			}
+		})
+	}
+	client := newTestClient(t, c)
+
+	client.send(0, "test/append", "a")
+	client.send(1, "test/log", nil)
+	expectResult(t, client.recv(), 1, []string{"a", "intercepted"})
+
+	got, _ := calls.get(context.Background(), nil)
+	expected := []string{
+		"outer initialize -1", "inner initialize -1",
+		"outer test/append ", "inner test/append ",
+		"outer test/log 1", "inner test/log 1",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("got %#v, expected %#v", got, expected)
+	}
+}
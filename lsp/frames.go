@@ -12,10 +12,6 @@ import (
 	"strings"
 )
 
-// FrameLogger can be used to log frames.
-// The first parameter is either "send" or "recv", depending on the direction of the frame.
-var FrameLogger = func(string, []byte) {}
-
 // A Frame is represents the wire-format of JSON RPC.
 // It can be one of several things:
 // - a request (Id, Method, Params)
@@ -31,6 +27,42 @@ type Frame struct {
 	Result  json.RawMessage `json:"result,omitempty"`
 	Error   *RpcError       `json:"error,omitempty"`
 	Batch   []*Frame        `json:"-"`
+	// invalid is set on an element of a batch that could not be decoded,
+	// which is answered with an Invalid Request error.
+	invalid error
+}
+
+// MarshalJSON encodes a batch frame as an array of its frames,
+// and any other frame as a JSON object.
+func (f *Frame) MarshalJSON() ([]byte, error) {
+	if f.Batch != nil {
+		return json.Marshal(f.Batch)
+	}
+	type frame Frame
+	return json.Marshal((*frame)(f))
+}
+
+// UnmarshalJSON decodes an array as a batch frame,
+// and an object as any other frame. Each element of a batch is
+// decoded separately, so that one invalid element does not
+// prevent the others from being handled.
+func (f *Frame) UnmarshalJSON(data []byte) error {
+	type frame Frame
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		elements := []json.RawMessage{}
+		if err := json.Unmarshal(trimmed, &elements); err != nil {
+			return err
+		}
+		f.Batch = make([]*Frame, len(elements))
+		for i, element := range elements {
+			f.Batch[i] = &Frame{}
+			if err := json.Unmarshal(element, (*frame)(f.Batch[i])); err != nil {
+				f.Batch[i] = &Frame{invalid: fmt.Errorf("invalid request: %w", err)}
+			}
+		}
+		return nil
+	}
+	return json.Unmarshal(data, (*frame)(f))
 }
 
 type RpcError struct {
@@ -38,6 +70,10 @@ type RpcError struct {
 	Message string    `json:"message"`
 }
 
+func (e *RpcError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
 // WriteFrames writes successive frames to the given writer
 // until either it returns an error, or the channel is closed
 func WriteFrames(ctx context.Context, w io.Writer, ch <-chan *Frame) error {
@@ -56,17 +92,16 @@ func WriteFrames(ctx context.Context, w io.Writer, ch <-chan *Frame) error {
 		select {
 		case <-ctx.Done():
 			return nil
-		case frame := <-ch:
+		case frame, ok := <-ch:
+			if !ok {
+				return nil
+			}
 			msg, err := json.Marshal(frame)
 			if err != nil {
 				panic(err)
 			}
-			FrameLogger("send", msg)
 			header := fmt.Sprintf("Content-Length: %d\r\n\r\n", len(msg))
-			if err := writeAll([]byte(header)); err != nil {
-				return err
-			}
-			if err := writeAll(msg); err != nil {
+			if err := writeAll(append([]byte(header), msg...)); err != nil {
 				return err
 			}
 		}
@@ -91,7 +126,6 @@ func ReadFrames(r io.Reader) iter.Seq2[*Frame, error] {
 					if err == io.EOF && len(headers) > 0 {
 						err = io.ErrUnexpectedEOF
 					}
-					FrameLogger("recv error", []byte(err.Error()))
 					if err != io.EOF {
 						yield(nil, err)
 					}
@@ -113,7 +147,6 @@ func ReadFrames(r io.Reader) iter.Seq2[*Frame, error] {
 			}
 
 			if frameErr != nil {
-				FrameLogger("recv error", []byte(err.Error()))
 				yield(nil, frameErr)
 				return
 			}
@@ -124,26 +157,13 @@ func ReadFrames(r io.Reader) iter.Seq2[*Frame, error] {
 				if err == io.EOF {
 					err = io.ErrUnexpectedEOF
 				}
-				FrameLogger("recv error", []byte(err.Error()))
 				yield(nil, err)
 				return
 			}
-			FrameLogger("recv", buf)
 			frame := Frame{}
-			if bytes.HasPrefix(buf, []byte("[")) {
-				frames := []*Frame{}
-				err = json.Unmarshal(buf, &frames)
-				if err != nil {
-					yield(nil, err)
-					return
-				}
-				frame.Batch = frames
-			} else {
-				err = json.Unmarshal(buf, &frame)
-				if err != nil {
-					yield(nil, err)
-					return
-				}
+			if err := json.Unmarshal(buf, &frame); err != nil {
+				yield(nil, err)
+				return
 			}
 			if !yield(&frame, nil) {
 				return
@@ -0,0 +1,3 @@
+package lsp
+
+//go:generate go run ./internal/generate -model metaModel.json -out protocol.go
@@ -0,0 +1,645 @@
+// Command generate writes the lsp package's protocol types from the
+// LSP metaModel.json published alongside the specification.
+//
+// Every structure, enumeration and type alias in the model is generated.
+// References to types that the model does not define (if it is a subset of
+// the specification) are generated as json.RawMessage.
+//
+// Properties whose type is a union of structures, enumerations and base
+// types (like boolean | HoverOptions) are generated as an interface that
+// holds one of them, and the structure gets MarshalJSON and UnmarshalJSON
+// methods that check and decode the value. Other unions, and unions in
+// structures that are embedded in others, are generated as json.RawMessage.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const specURL = "https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/"
+
+// MetaModel is the subset of the metaModel.json schema used by the generator.
+type MetaModel struct {
+	MetaData struct {
+		Version string `json:"version"`
+	} `json:"metaData"`
+	Requests      []Method      `json:"requests"`
+	Notifications []Method      `json:"notifications"`
+	Structures    []Structure   `json:"structures"`
+	Enumerations  []Enumeration `json:"enumerations"`
+	TypeAliases   []TypeAlias   `json:"typeAliases"`
+}
+
+type Method struct {
+	Method   string `json:"method"`
+	Proposed bool   `json:"proposed"`
+}
+
+type Structure struct {
+	Name       string     `json:"name"`
+	Extends    []*Type    `json:"extends"`
+	Mixins     []*Type    `json:"mixins"`
+	Properties []Property `json:"properties"`
+	Proposed   bool       `json:"proposed"`
+}
+
+type Property struct {
+	Name     string `json:"name"`
+	Type     *Type  `json:"type"`
+	Optional bool   `json:"optional"`
+	Proposed bool   `json:"proposed"`
+}
+
+type Enumeration struct {
+	Name   string `json:"name"`
+	Type   *Type  `json:"type"`
+	Values []struct {
+		Name     string          `json:"name"`
+		Value    json.RawMessage `json:"value"`
+		Proposed bool            `json:"proposed"`
+	} `json:"values"`
+	Proposed bool `json:"proposed"`
+}
+
+type TypeAlias struct {
+	Name     string `json:"name"`
+	Type     *Type  `json:"type"`
+	Proposed bool   `json:"proposed"`
+}
+
+// Type is a type reference. Kind is one of base, reference, array, map,
+// and, or, tuple, literal, stringLiteral, integerLiteral or booleanLiteral.
+type Type struct {
+	Kind    string          `json:"kind"`
+	Name    string          `json:"name"`
+	Element *Type           `json:"element"`
+	Key     *Type           `json:"key"`
+	Value   json.RawMessage `json:"value"`
+	Items   []*Type         `json:"items"`
+}
+
+func main() {
+	model := flag.String("model", "metaModel.json", "the metaModel.json to read")
+	out := flag.String("out", "protocol.go", "the file to write")
+	flag.Parse()
+
+	src, err := generateFile(*model)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// generateFile generates the protocol types from the model at modelPath.
+func generateFile(modelPath string) ([]byte, error) {
+	f, err := os.Open(modelPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return generate(f)
+}
+
+// literalNames names the literal types of some properties, which are
+// anonymous in the 3.17 model, as later versions of the model do so
+// that values of them can be constructed.
+var literalNames = map[string]string{
+	"_InitializeParams.clientInfo": "ClientInfo",
+	"InitializeResult.serverInfo":  "ServerInfo",
+}
+
+type generator struct {
+	buf        bytes.Buffer
+	kinds      map[string]string
+	structures map[string]Structure
+	aliases    map[string]*Type
+	// embedded are the structures that other structures extend or mix in
+	embedded map[string]bool
+	// literals are the named literal types found while generating properties
+	literals map[string]literal
+	// unions are the members of the union types found while generating properties
+	unions map[string][]*Type
+	// structure is the structure whose fields are being generated, and
+	// unionFields are its fields whose type is a union
+	structure   string
+	unionFields []unionField
+}
+
+type literal struct {
+	owner string
+	typ   *Type
+}
+
+type unionField struct {
+	name  string
+	tag   string
+	union string
+	array bool
+}
+
+func generate(r io.Reader) ([]byte, error) {
+	model := &MetaModel{}
+	if err := json.NewDecoder(r).Decode(model); err != nil {
+		return nil, fmt.Errorf("invalid metaModel: %w", err)
+	}
+
+	g := &generator{
+		kinds:      map[string]string{},
+		structures: map[string]Structure{},
+		aliases:    map[string]*Type{},
+		embedded:   map[string]bool{},
+		literals:   map[string]literal{},
+		unions:     map[string][]*Type{},
+	}
+	for _, s := range model.Structures {
+		g.kinds[s.Name] = "structure"
+		g.structures[s.Name] = s
+		for _, t := range append(slices.Clone(s.Extends), s.Mixins...) {
+			g.embedded[t.Name] = true
+		}
+	}
+	for _, e := range model.Enumerations {
+		g.kinds[e.Name] = "enumeration"
+	}
+	for _, a := range model.TypeAliases {
+		g.kinds[a.Name] = "alias"
+		g.aliases[a.Name] = a.Type
+	}
+
+	methods := append(slices.Clone(model.Requests), model.Notifications...)
+	slices.SortFunc(methods, func(a, b Method) int { return strings.Compare(a.Method, b.Method) })
+	g.printf("// The methods defined by the specification.\nconst (\n")
+	for _, m := range methods {
+		if !m.Proposed {
+			g.printf("Method%s = %q\n", methodName(m.Method), m.Method)
+		}
+	}
+	g.printf(")\n\n")
+
+	structures := slices.Clone(model.Structures)
+	slices.SortFunc(structures, func(a, b Structure) int { return strings.Compare(a.Name, b.Name) })
+	for _, s := range structures {
+		// structures named _X are only used to declare X
+		if s.Proposed || strings.HasPrefix(s.Name, "_") {
+			continue
+		}
+		g.printf("// %s#%s\ntype %s struct {\n", specURL, lowerFirst(s.Name), s.Name)
+		g.structure, g.unionFields = s.Name, nil
+		g.fields(s)
+		g.printf("}\n\n")
+		if len(g.unionFields) > 0 {
+			g.unionMethods(s.Name)
+		}
+	}
+	g.structure = ""
+
+	literals := slices.Sorted(maps.Keys(g.literals))
+	for _, name := range literals {
+		l := g.literals[name]
+		g.printf("// %s#%s\ntype %s %s\n\n", specURL, lowerFirst(strings.TrimPrefix(l.owner, "_")), name, g.goType(l.typ))
+	}
+
+	enumerations := slices.Clone(model.Enumerations)
+	slices.SortFunc(enumerations, func(a, b Enumeration) int { return strings.Compare(a.Name, b.Name) })
+	for _, e := range enumerations {
+		if e.Proposed {
+			continue
+		}
+		g.printf("// %s#%s\ntype %s %s\n\nconst (\n", specURL, lowerFirst(e.Name), e.Name, g.goType(e.Type))
+		for _, v := range e.Values {
+			if !v.Proposed {
+				g.printf("%s%s %s = %s\n", e.Name, upperFirst(v.Name), e.Name, v.Value)
+			}
+		}
+		g.printf(")\n\n")
+	}
+
+	aliases := slices.Clone(model.TypeAliases)
+	slices.SortFunc(aliases, func(a, b TypeAlias) int { return strings.Compare(a.Name, b.Name) })
+	for _, a := range aliases {
+		if a.Proposed {
+			continue
+		}
+		// aliases of literals are declared as structs so that they have a name
+		typ := g.goType(a.Type)
+		if strings.HasPrefix(typ, "struct {") {
+			g.printf("// %s#%s\ntype %s %s\n\n", specURL, lowerFirst(a.Name), a.Name, typ)
+		} else {
+			g.printf("// %s#%s\ntype %s = %s\n\n", specURL, lowerFirst(a.Name), a.Name, typ)
+		}
+	}
+
+	unions := slices.Sorted(maps.Keys(g.unions))
+	for _, name := range unions {
+		g.unionType(name, g.unions[name])
+	}
+
+	header := fmt.Sprintf("// Code generated by lsp/internal/generate from metaModel.json (LSP %s). DO NOT EDIT.\n\npackage lsp\n\n", model.MetaData.Version)
+	if bytes.Contains(g.buf.Bytes(), []byte("json.")) {
+		header += "import \"encoding/json\"\n\n"
+	}
+	src, err := format.Source(append([]byte(header), g.buf.Bytes()...))
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid Go: %w\n%s", err, g.buf.Bytes())
+	}
+	return src, nil
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// fields generates the fields of s. The structures it extends (or mixes in)
+// are embedded, except for those named _X whose properties are copied.
+func (g *generator) fields(s Structure) {
+	for _, t := range append(slices.Clone(s.Extends), s.Mixins...) {
+		if strings.HasPrefix(t.Name, "_") {
+			g.fields(g.structures[t.Name])
+		} else {
+			g.printf("%s\n", t.Name)
+		}
+	}
+	g.properties(s.Name, s.Properties)
+}
+
+// properties generates a field for each property of the structure named owner.
+func (g *generator) properties(owner string, properties []Property) {
+	for _, p := range properties {
+		if p.Proposed {
+			continue
+		}
+		tag := p.Name
+		typ := g.goType(p.Type)
+		if name, ok := literalNames[owner+"."+p.Name]; ok && p.Type.Kind == "literal" {
+			g.literals[name] = literal{owner, p.Type}
+			typ = name
+		}
+		if name, ok := g.union(p.Type); ok {
+			g.unionFields = append(g.unionFields, unionField{fieldName(p.Name), p.Name, name, false})
+			typ = name
+		} else if p.Type.Kind == "array" {
+			if name, ok := g.union(p.Type.Element); ok {
+				g.unionFields = append(g.unionFields, unionField{fieldName(p.Name), p.Name, name, true})
+				typ = "[]" + name
+			}
+		}
+		if p.Optional {
+			tag += ",omitempty"
+			typ = g.optionalType(p.Type, typ)
+		}
+		g.printf("%s %s `json:%q`\n", fieldName(p.Name), typ, tag)
+	}
+}
+
+// optionalType returns the type to use for an optional property. Structures
+// and numbers are pointers, so that a zero value can be distinguished from
+// a missing one; other types use their zero value for missing.
+func (g *generator) optionalType(t *Type, typ string) string {
+	if strings.HasPrefix(typ, "*") {
+		return typ
+	}
+	switch {
+	case t.Kind == "base" && (t.Name == "integer" || t.Name == "uinteger" || t.Name == "decimal"):
+		return "*" + typ
+	case t.Kind == "reference" && g.kinds[t.Name] == "structure":
+		return "*" + typ
+	case t.Kind == "literal":
+		return "*" + typ
+	}
+	return typ
+}
+
+func (g *generator) goType(t *Type) string {
+	switch t.Kind {
+	case "base":
+		switch t.Name {
+		case "DocumentUri", "URI":
+			return "DocumentURI"
+		case "RegExp", "string":
+			return "string"
+		case "integer":
+			return "int32"
+		case "uinteger":
+			return "uint32"
+		case "decimal":
+			return "float64"
+		case "boolean":
+			return "bool"
+		}
+	case "reference":
+		if g.kinds[t.Name] == "" {
+			return "json.RawMessage"
+		}
+		return t.Name
+	case "array":
+		return "[]" + g.goType(t.Element)
+	case "map":
+		return "map[" + g.goType(t.Key) + "]" + g.goType(t.Element)
+	case "or":
+		// A | null is a *A, a union of literals is a struct with the
+		// properties of all of them, and anything else must be decoded
+		// by the caller.
+		items := slices.DeleteFunc(slices.Clone(t.Items), func(item *Type) bool {
+			return item.Kind == "base" && item.Name == "null"
+		})
+		if len(items) == 1 && len(t.Items) == 2 {
+			typ := g.goType(items[0])
+			if strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") || typ == "json.RawMessage" {
+				return typ
+			}
+			return "*" + typ
+		}
+		if !slices.ContainsFunc(items, func(item *Type) bool { return item.Kind != "literal" }) {
+			return g.literalType(mergeLiterals(items))
+		}
+	case "literal":
+		if properties, err := literalProperties(t); err == nil {
+			return g.literalType(properties)
+		}
+	case "stringLiteral":
+		return "string"
+	case "integerLiteral":
+		return "int32"
+	case "booleanLiteral":
+		return "bool"
+	}
+	return "json.RawMessage"
+}
+
+func (g *generator) literalType(properties []Property) string {
+	inner := &generator{kinds: g.kinds, structures: g.structures, literals: g.literals}
+	inner.properties("", properties)
+	return "struct {\n" + inner.buf.String() + "}"
+}
+
+// union returns the name of the interface to generate for t if t is a
+// union that can be decoded by looking at the JSON: one whose members are
+// base types, enumerations, aliases and structures (or arrays of them), at
+// least one of which is a structure.
+func (g *generator) union(t *Type) (string, bool) {
+	if t.Kind != "or" || g.structure == "" || g.embedded[g.structure] {
+		return "", false
+	}
+	members := slices.DeleteFunc(slices.Clone(t.Items), func(item *Type) bool {
+		return item.Kind == "base" && item.Name == "null"
+	})
+	if len(members) < 2 {
+		return "", false
+	}
+	structure := false
+	names := []string{}
+	for _, m := range members {
+		e := m
+		if e.Kind == "array" {
+			e = e.Element
+		}
+		switch {
+		case e.Kind == "base":
+		case e.Kind == "reference" && g.kinds[e.Name] != "":
+			structure = structure || g.kinds[e.Name] == "structure"
+		default:
+			return "", false
+		}
+		names = append(names, g.memberName(m))
+	}
+	if !structure {
+		return "", false
+	}
+	name := strings.Join(names, "Or")
+	g.unions[name] = members
+	return name, true
+}
+
+// memberName names a member of a union, as part of the union's name.
+func (g *generator) memberName(t *Type) string {
+	switch t.Kind {
+	case "array":
+		return g.memberName(t.Element) + "s"
+	case "reference":
+		return t.Name
+	}
+	return upperFirst(g.goType(t))
+}
+
+// memberType returns the Go type that a union holds for the member t.
+// Structures are held as pointers.
+func (g *generator) memberType(t *Type) string {
+	typ := g.goType(t)
+	if t.Kind == "reference" && (g.kinds[t.Name] == "structure" || g.kinds[t.Name] == "alias" && strings.HasPrefix(g.goType(g.aliases[t.Name]), "struct {")) {
+		return "*" + typ
+	}
+	return typ
+}
+
+// required returns the properties that a JSON object must have to be a
+// value of the structure (or alias of literals) named name, and the values
+// of those properties that are string literals.
+func (g *generator) required(name string) ([]string, map[string]string) {
+	var properties []Property
+	if t, ok := g.aliases[name]; ok {
+		if t.Kind == "or" {
+			properties = mergeLiterals(t.Items)
+		} else {
+			properties, _ = literalProperties(t)
+		}
+	} else {
+		properties = g.structureProperties(name)
+	}
+	required := []string{}
+	literals := map[string]string{}
+	for _, p := range properties {
+		if p.Optional || p.Proposed {
+			continue
+		}
+		required = append(required, p.Name)
+		if p.Type.Kind == "stringLiteral" {
+			var value string
+			json.Unmarshal(p.Type.Value, &value)
+			literals[p.Name] = value
+		}
+	}
+	return required, literals
+}
+
+// structureProperties returns the properties of the structure named name,
+// including those of the structures it extends unless it redeclares them.
+func (g *generator) structureProperties(name string) []Property {
+	s := g.structures[name]
+	properties := []Property{}
+	for _, t := range append(slices.Clone(s.Extends), s.Mixins...) {
+		properties = append(properties, g.structureProperties(t.Name)...)
+	}
+	for _, p := range s.Properties {
+		properties = slices.DeleteFunc(properties, func(q Property) bool { return q.Name == p.Name })
+		properties = append(properties, p)
+	}
+	return properties
+}
+
+// unionType generates the interface for the union named name, and the
+// members that unmarshalUnion chooses between to decode it.
+func (g *generator) unionType(name string, members []*Type) {
+	types := []string{}
+	for _, m := range members {
+		types = append(types, g.memberType(m))
+	}
+	g.printf("// %s holds a %s or %s.\ntype %s interface{}\n\n", name, strings.Join(types[:len(types)-1], ", "), types[len(types)-1], name)
+	g.printf("var %s = []unionMember{\n", lowerFirst(name))
+	for _, m := range members {
+		g.printf("{value: new(%s)", g.goType(m))
+		if strings.HasPrefix(g.memberType(m), "*") {
+			required, literals := g.required(m.Name)
+			if len(required) > 0 {
+				g.printf(", required: %#v", required)
+			}
+			if len(literals) > 0 {
+				g.printf(", literals: %#v", literals)
+			}
+		}
+		g.printf("},\n")
+	}
+	g.printf("}\n\n")
+}
+
+// unionMethods generates the MarshalJSON and UnmarshalJSON methods of the
+// structure named name, which check and decode its union-typed fields.
+func (g *generator) unionMethods(name string) {
+	g.printf("func (s %s) MarshalJSON() ([]byte, error) {\n", name)
+	for _, f := range g.unionFields {
+		if f.array {
+			g.printf("for _, v := range s.%s {\nif err := checkUnion(%q, v, %s); err != nil {\nreturn nil, err\n}\n}\n", f.name, f.tag, lowerFirst(f.union))
+		} else {
+			g.printf("if err := checkUnion(%q, s.%s, %s); err != nil {\nreturn nil, err\n}\n", f.tag, f.name, lowerFirst(f.union))
+		}
+	}
+	g.printf("type plain %s\nreturn json.Marshal(plain(s))\n}\n\n", name)
+
+	g.printf("func (s *%s) UnmarshalJSON(data []byte) error {\ntype plain %s\nraw := struct {\n*plain\n", name, name)
+	for _, f := range g.unionFields {
+		typ := "json.RawMessage"
+		if f.array {
+			typ = "[]" + typ
+		}
+		g.printf("%s %s `json:%q`\n", f.name, typ, f.tag)
+	}
+	g.printf("}{plain: (*plain)(s)}\nif err := json.Unmarshal(data, &raw); err != nil {\nreturn err\n}\nvar err error\n")
+	for _, f := range g.unionFields {
+		if f.array {
+			g.printf("if raw.%s != nil {\ns.%s = make([]%s, len(raw.%s))\n}\n", f.name, f.name, f.union, f.name)
+			g.printf("for i, v := range raw.%s {\nif s.%s[i], err = unmarshalUnion(%q, v, %s); err != nil {\nreturn err\n}\n}\n", f.name, f.name, f.tag, lowerFirst(f.union))
+		} else {
+			g.printf("if s.%s, err = unmarshalUnion(%q, raw.%s, %s); err != nil {\nreturn err\n}\n", f.name, f.tag, f.name, lowerFirst(f.union))
+		}
+	}
+	g.printf("return nil\n}\n\n")
+}
+
+func literalProperties(t *Type) ([]Property, error) {
+	value := struct {
+		Properties []Property `json:"properties"`
+	}{}
+	err := json.Unmarshal(t.Value, &value)
+	return value.Properties, err
+}
+
+// mergeLiterals returns the properties of all of the literals, which are
+// optional unless every literal has them.
+func mergeLiterals(literals []*Type) []Property {
+	merged := []Property{}
+	counts := map[string]int{}
+	for _, t := range literals {
+		properties, _ := literalProperties(t)
+		for _, p := range properties {
+			counts[p.Name]++
+			if counts[p.Name] == 1 {
+				merged = append(merged, p)
+			}
+		}
+	}
+	for i, p := range merged {
+		if counts[p.Name] < len(literals) {
+			merged[i].Optional = true
+		}
+	}
+	return merged
+}
+
+// UnmarshalJSON moves the value type of a map into Element, as the value
+// of other kinds of type is not a type.
+func (t *Type) UnmarshalJSON(data []byte) error {
+	type plain Type
+	if err := json.Unmarshal(data, (*plain)(t)); err != nil {
+		return err
+	}
+	if t.Kind != "map" {
+		return nil
+	}
+	value := &Type{}
+	if err := json.Unmarshal(t.Value, value); err != nil {
+		return err
+	}
+	t.Value = nil
+	t.Element = value
+	return nil
+}
+
+var initialisms = map[string]string{"id": "ID", "ids": "IDs", "uri": "URI", "uris": "URIs", "url": "URL", "json": "JSON"}
+
+// fieldName converts a camelCase property name to a Go field name,
+// with initialisms in upper case (e.g. languageId becomes LanguageID).
+func fieldName(name string) string {
+	words := []string{}
+	start := 0
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			words = append(words, name[start:i])
+			start = i
+		}
+	}
+	words = append(words, name[start:])
+	for i, word := range words {
+		if initialism, ok := initialisms[strings.ToLower(word)]; ok {
+			words[i] = initialism
+		} else {
+			words[i] = upperFirst(word)
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// methodName converts a method like textDocument/didOpen to TextDocumentDidOpen.
+func methodName(method string) string {
+	name := ""
+	for _, part := range strings.Split(strings.TrimPrefix(method, "$/"), "/") {
+		name += upperFirst(part)
+	}
+	return name
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
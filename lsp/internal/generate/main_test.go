@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestGeneratedIsUpToDate(t *testing.T) {
+	expected, err := generateFile("../../metaModel.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile("../../protocol.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, expected) {
+		t.Fatal("lsp/protocol.go is out of date, run go generate ./lsp")
+	}
+}
+
+func TestFieldName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"uri":               "URI",
+		"languageId":        "LanguageID",
+		"previousResultId":  "PreviousResultID",
+		"workDoneToken":     "WorkDoneToken",
+		"rootUri":           "RootURI",
+		"previousResultIds": "PreviousResultIDs",
+	} {
+		if got := fieldName(name); got != expected {
+			t.Errorf("fieldName(%#v) = %#v, expected %#v", name, got, expected)
+		}
+	}
+}
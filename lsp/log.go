@@ -0,0 +1,138 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// clientLogHandler forwards warnings and errors to the client as
+// window/logMessage notifications, in addition to passing all records
+// to the underlying handler.
+type clientLogHandler struct {
+	handler slog.Handler
+	c       *Connection
+	attrs   []slog.Attr
+}
+
+func (h *clientLogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= slog.LevelWarn || h.handler.Enabled(ctx, level)
+}
+
+func (h *clientLogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelWarn {
+		msg := &strings.Builder{}
+		msg.WriteString(r.Message)
+		for _, attr := range h.attrs {
+			fmt.Fprintf(msg, " %s=%v", attr.Key, attr.Value)
+		}
+		r.Attrs(func(attr slog.Attr) bool {
+			fmt.Fprintf(msg, " %s=%v", attr.Key, attr.Value)
+			return true
+		})
+		h.c.Notify("window/logMessage", &LogMessageParams{
+			Type:    messageTypeFor(r.Level),
+			Message: msg.String(),
+		})
+	}
+	if h.handler.Enabled(ctx, r.Level) {
+		return h.handler.Handle(ctx, r)
+	}
+	return nil
+}
+
+func (h *clientLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &clientLogHandler{
+		handler: h.handler.WithAttrs(attrs),
+		c:       h.c,
+		attrs:   append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...),
+	}
+}
+
+func (h *clientLogHandler) WithGroup(name string) slog.Handler {
+	return &clientLogHandler{handler: h.handler.WithGroup(name), c: h.c, attrs: h.attrs}
+}
+
+func messageTypeFor(level slog.Level) MessageType {
+	switch {
+	case level >= slog.LevelError:
+		return MessageTypeError
+	case level >= slog.LevelWarn:
+		return MessageTypeWarning
+	case level >= slog.LevelInfo:
+		return MessageTypeInfo
+	default:
+		return MessageTypeLog
+	}
+}
+
+// traceFrame logs the frame at debug level, and if the client has enabled
+// tracing (with $/setTrace, or in initialize) sends a $/logTrace describing it.
+func (c *Connection) traceFrame(direction string, frame *Frame) {
+	c.mutex.Lock()
+	trace := c.trace
+	c.mutex.Unlock()
+
+	if frame.Method == "$/logTrace" {
+		return
+	}
+	if c.logger.Enabled(context.Background(), slog.LevelDebug) {
+		raw, _ := json.Marshal(frame)
+		c.logger.Debug(direction, "frame", string(raw))
+	}
+	if trace != TraceValuesMessages && trace != TraceValuesVerbose {
+		return
+	}
+
+	prefix := "Received "
+	if direction == "send" {
+		prefix = "Sending "
+	}
+	frames := []*Frame{frame}
+	if frame.Batch != nil {
+		frames = frame.Batch
+	}
+	for _, f := range frames {
+		params := &LogTraceParams{Message: prefix + describeFrame(f)}
+		if trace == TraceValuesVerbose {
+			if f.Method != "" {
+				params.Verbose = "Params: " + string(f.Params)
+			} else if f.Error != nil {
+				params.Verbose = "Error: " + f.Error.Error()
+			} else {
+				params.Verbose = "Result: " + string(f.Result)
+			}
+		}
+		c.Notify("$/logTrace", params)
+	}
+}
+
+func describeFrame(f *Frame) string {
+	switch {
+	case f.Method != "" && f.Id != nil:
+		return fmt.Sprintf("request '%s - (%s)'", f.Method, f.Id)
+	case f.Method != "":
+		return fmt.Sprintf("notification '%s'", f.Method)
+	default:
+		return fmt.Sprintf("response '(%s)'", f.Id)
+	}
+}
+
+// SetTrace sets the level of protocol tracing sent to the client with $/logTrace.
+func (c *Connection) SetTrace(value TraceValues) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.trace = value
+}
+
+func (c *Connection) setTrace(ctx context.Context, params *SetTraceParams) {
+	c.SetTrace(params.Value)
+}
+
+// Logger returns a logger that writes to slog.Default(), and also
+// forwards warnings and errors to the client.
+func (c *Connection) Logger() *slog.Logger {
+	return c.logger
+}
@@ -0,0 +1,64 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+)
+
+// A Call is an incoming request or notification, as seen by middleware.
+// ID is nil for notifications, and Params is the decoded value that will be
+// passed to the handler.
+type Call struct {
+	Method string
+	ID     json.RawMessage
+	Params any
+}
+
+// A Handler handles a Call. For notifications the result is ignored.
+type Handler func(ctx context.Context, call *Call) (any, error)
+
+// Middleware wraps every handler registered on a Connection.
+type Middleware func(next Handler) Handler
+
+// Use adds middleware that is run around every request and notification handler.
+// The first middleware added is the outermost. Use must be called before Serve.
+func (c *Connection) Use(middleware ...Middleware) {
+	c.middleware = append(c.middleware, middleware...)
+}
+
+// wrap returns the registered handler wrapped in all middleware.
+func (c *Connection) wrap(h handler) Handler {
+	next := func(ctx context.Context, call *Call) (any, error) {
+		if h.notification != nil {
+			h.notification(ctx, call.Params)
+			return nil, nil
+		}
+		return h.request(ctx, call.Params)
+	}
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		next = c.middleware[i](next)
+	}
+	return next
+}
+
+// LogCalls is middleware that logs each call at debug level, along with how
+// long it took and any error it returned.
+func LogCalls(logger *slog.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (any, error) {
+			start := time.Now()
+			result, err := next(ctx, call)
+			args := []any{"method", call.Method, "duration", time.Since(start)}
+			if call.ID != nil {
+				args = append(args, "id", string(call.ID))
+			}
+			if err != nil {
+				args = append(args, "error", err)
+			}
+			logger.Debug("handled", args...)
+			return result, err
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package lsp
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// maxQueuedFrames bounds the number of frames waiting to be written.
+// When the queue is full, sending blocks until the client catches up.
+const maxQueuedFrames = 1024
+
+// outbox is the queue of frames waiting to be written to the client.
+//
+// Diagnostics are coalesced: if a textDocument/publishDiagnostics for a
+// document is still queued when a newer one is sent, the queued frame is
+// replaced so that the client only receives the latest results.
+type outbox struct {
+	mutex       sync.Mutex
+	frames      []*Frame
+	diagnostics map[DocumentURI]*queuedDiagnostics
+	closed      bool
+	notEmpty    chan struct{}
+	notFull     chan struct{}
+}
+
+type queuedDiagnostics struct {
+	index   int
+	version *int32
+}
+
+func newOutbox() *outbox {
+	return &outbox{
+		diagnostics: map[DocumentURI]*queuedDiagnostics{},
+		notEmpty:    make(chan struct{}, 1),
+		notFull:     make(chan struct{}, 1),
+	}
+}
+
+// push queues a frame, blocking while the queue is full. It returns false
+// (and drops the frame) if the outbox is closed, or done is closed first.
+func (o *outbox) push(frame *Frame, done <-chan struct{}) bool {
+	for {
+		o.mutex.Lock()
+		if o.closed {
+			o.mutex.Unlock()
+			return false
+		}
+		if o.coalesce(frame) {
+			o.mutex.Unlock()
+			return true
+		}
+		if len(o.frames) < maxQueuedFrames {
+			o.append(frame)
+			if len(o.frames) < maxQueuedFrames {
+				signal(o.notFull)
+			}
+			o.mutex.Unlock()
+			signal(o.notEmpty)
+			return true
+		}
+		o.mutex.Unlock()
+
+		select {
+		case <-o.notFull:
+		case <-done:
+			return false
+		}
+	}
+}
+
+// coalesce replaces a queued publishDiagnostics for the same document,
+// and reports whether it did so. A frame for an older version than the
+// queued one is dropped. Frames without a version (such as the empty
+// diagnostics sent when a document is closed) always replace the queued one.
+func (o *outbox) coalesce(frame *Frame) bool {
+	if frame.Method != "textDocument/publishDiagnostics" {
+		return false
+	}
+	params := PublishDiagnosticsParams{}
+	if err := json.Unmarshal(frame.Params, &params); err != nil {
+		return false
+	}
+	queued, ok := o.diagnostics[params.URI]
+	if !ok {
+		return false
+	}
+	if params.Version == nil || queued.version == nil || *params.Version >= *queued.version {
+		o.frames[queued.index] = frame
+		queued.version = params.Version
+	}
+	return true
+}
+
+func (o *outbox) append(frame *Frame) {
+	o.frames = append(o.frames, frame)
+	if frame.Method == "textDocument/publishDiagnostics" {
+		params := PublishDiagnosticsParams{}
+		if err := json.Unmarshal(frame.Params, &params); err == nil {
+			o.diagnostics[params.URI] = &queuedDiagnostics{index: len(o.frames) - 1, version: params.Version}
+		}
+	}
+}
+
+// pop removes the next frame from the queue, blocking until there is one.
+// It returns false once the outbox is closed and empty, or done is closed.
+func (o *outbox) pop(done <-chan struct{}) (*Frame, bool) {
+	for {
+		o.mutex.Lock()
+		if len(o.frames) > 0 {
+			frame := o.frames[0]
+			o.frames = o.frames[1:]
+			for uri, queued := range o.diagnostics {
+				if queued.index == 0 {
+					delete(o.diagnostics, uri)
+				} else {
+					queued.index--
+				}
+			}
+			o.mutex.Unlock()
+			signal(o.notFull)
+			return frame, true
+		}
+		closed := o.closed
+		o.mutex.Unlock()
+		if closed {
+			return nil, false
+		}
+
+		select {
+		case <-o.notEmpty:
+		case <-done:
+			return nil, false
+		}
+	}
+}
+
+// close stops further frames from being queued. Frames already queued
+// are still returned by pop.
+func (o *outbox) close() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.closed = true
+	signal(o.notEmpty)
+}
+
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
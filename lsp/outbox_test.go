@@ -0,0 +1,128 @@
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func diagnosticsFrame(uri DocumentURI, version int32) *Frame {
+	return &Frame{
+		JsonRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params:  mustMarshal(&PublishDiagnosticsParams{URI: uri, Version: &version, Diagnostics: []Diagnostic{}}),
+	}
+}
+
+func TestOutboxCoalescesDiagnostics(t *testing.T) {
+	o := newOutbox()
+	done := make(chan struct{})
+	o.push(diagnosticsFrame("file:///a.conl", 1), done)
+	o.push(&Frame{JsonRPC: "2.0", Method: "window/logMessage"}, done)
+	o.push(diagnosticsFrame("file:///b.conl", 1), done)
+	o.push(diagnosticsFrame("file:///a.conl", 3), done)
+	o.push(diagnosticsFrame("file:///a.conl", 2), done)
+	o.close()
+
+	expected := []string{"file:///a.conl@3", "window/logMessage", "file:///b.conl@1"}
+	for _, e := range expected {
+		frame, ok := o.pop(done)
+		if !ok {
+			t.Fatalf("expected %s, got nothing", e)
+		}
+		got := frame.Method
+		if frame.Method == "textDocument/publishDiagnostics" {
+			params := PublishDiagnosticsParams{}
+			json.Unmarshal(frame.Params, &params)
+			got = fmt.Sprintf("%s@%d", params.URI, *params.Version)
+		}
+		if got != e {
+			t.Fatalf("got %s, expected %s", got, e)
+		}
+	}
+	if frame, ok := o.pop(done); ok {
+		t.Fatalf("unexpected %#v", frame)
+	}
+
+	// once sent, diagnostics for a document are no longer coalesced
+	o = newOutbox()
+	o.push(diagnosticsFrame("file:///a.conl", 1), done)
+	o.pop(done)
+	o.push(diagnosticsFrame("file:///a.conl", 2), done)
+	if frame, ok := o.pop(done); !ok || frame.Method != "textDocument/publishDiagnostics" {
+		t.Fatalf("got %#v, expected diagnostics", frame)
+	}
+}
+
+func TestOutboxKeepsClearedDiagnostics(t *testing.T) {
+	o := newOutbox()
+	done := make(chan struct{})
+	o.push(diagnosticsFrame("file:///a.conl", 3), done)
+	o.push(&Frame{
+		JsonRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params:  mustMarshal(&PublishDiagnosticsParams{URI: "file:///a.conl", Diagnostics: []Diagnostic{}}),
+	}, done)
+	o.close()
+
+	frame, ok := o.pop(done)
+	if !ok {
+		t.Fatal("expected diagnostics, got nothing")
+	}
+	params := PublishDiagnosticsParams{}
+	if err := json.Unmarshal(frame.Params, &params); err != nil {
+		t.Fatal(err)
+	}
+	if params.Version != nil {
+		t.Fatalf("got version %d, expected the clear to replace it", *params.Version)
+	}
+	if frame, ok := o.pop(done); ok {
+		t.Fatalf("unexpected %#v", frame)
+	}
+}
+
+func TestOutboxBackpressure(t *testing.T) {
+	o := newOutbox()
+	done := make(chan struct{})
+	for range maxQueuedFrames {
+		o.push(&Frame{JsonRPC: "2.0", Method: "test/log"}, done)
+	}
+
+	pushed := make(chan bool)
+	go func() { pushed <- o.push(&Frame{JsonRPC: "2.0", Method: "test/last"}, done) }()
+	select {
+	case <-pushed:
+		t.Fatal("push did not block when the queue was full")
+	case <-time.After(10 * time.Millisecond):
+	}
+	o.pop(done)
+	if !<-pushed {
+		t.Fatal("push failed after space was available")
+	}
+
+	go func() { pushed <- o.push(&Frame{JsonRPC: "2.0", Method: "test/dropped"}, done) }()
+	close(done)
+	if <-pushed {
+		t.Fatal("push succeeded after done was closed")
+	}
+}
+
+func TestNotifyAfterClose(t *testing.T) {
+	c := NewConnection()
+	newTestClient(t, c)
+	c.Exit()
+
+	finished := make(chan struct{})
+	go func() {
+		for range maxQueuedFrames + 1 {
+			c.Notify("test/log", nil)
+		}
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("Notify blocked after the connection closed")
+	}
+}
@@ -0,0 +1,87 @@
+package lsp
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// NegotiatePositionEncoding picks the first of the client's preferred encodings that is
+// supported, falling back to UTF-16 which all clients must support.
+func NegotiatePositionEncoding(preferred []PositionEncodingKind) PositionEncodingKind {
+	for _, kind := range preferred {
+		switch kind {
+		case PositionEncodingKindUTF8, PositionEncodingKindUTF16, PositionEncodingKindUTF32:
+			return kind
+		}
+	}
+	return PositionEncodingKindUTF16
+}
+
+// RuneLen returns the number of code units needed to encode r,
+// or -1 if r cannot be encoded.
+func (k PositionEncodingKind) RuneLen(r rune) int {
+	switch k {
+	case PositionEncodingKindUTF8:
+		return utf8.RuneLen(r)
+	case PositionEncodingKindUTF32:
+		if !utf8.ValidRune(r) {
+			return -1
+		}
+		return 1
+	default:
+		return utf16.RuneLen(r)
+	}
+}
+
+// Len returns the number of code units needed to encode s.
+func (k PositionEncodingKind) Len(s string) uint32 {
+	if k == PositionEncodingKindUTF8 {
+		return uint32(len(s))
+	}
+	ret := uint32(0)
+	for _, r := range s {
+		ret += uint32(k.RuneLen(r))
+	}
+	return ret
+}
+
+// ByteOffset converts a character offset within line to a byte offset.
+// Offsets past the end of the line are clamped to the end of the line.
+func (k PositionEncodingKind) ByteOffset(line string, character uint32) int {
+	pos := 0
+	for ix := 0; ix < len(line); {
+		if pos >= int(character) {
+			return ix
+		}
+		c, size := utf8.DecodeRuneInString(line[ix:])
+		pos += k.Width(c, size)
+		ix += size
+	}
+
+	return len(line)
+}
+
+// Character converts a byte offset within line to a character offset.
+func (k PositionEncodingKind) Character(line string, byteOffset int) uint32 {
+	pos := 0
+	for ix := 0; ix < len(line); {
+		if ix >= byteOffset {
+			return uint32(pos)
+		}
+		c, size := utf8.DecodeRuneInString(line[ix:])
+		pos += k.Width(c, size)
+		ix += size
+	}
+
+	return uint32(pos)
+}
+
+// Width returns the number of code units used by c, which was decoded from
+// size bytes. In UTF-8 this is the number of bytes, so that each byte of
+// invalid UTF-8 (decoded as utf8.RuneError) counts as one code unit.
+func (k PositionEncodingKind) Width(c rune, size int) int {
+	if k == PositionEncodingKindUTF8 {
+		return size
+	}
+	return k.RuneLen(c)
+}
@@ -0,0 +1,49 @@
+package lsp
+
+import "testing"
+
+func TestPositionEncodingKind(t *testing.T) {
+	line := "a😀é = b"
+	for _, test := range []struct {
+		kind      PositionEncodingKind
+		character uint32
+	}{
+		{PositionEncodingKindUTF8, 7},
+		{PositionEncodingKindUTF16, 4},
+		{PositionEncodingKindUTF32, 3},
+	} {
+		offset := len("a😀é")
+		if got := test.kind.Character(line, offset); got != test.character {
+			t.Errorf("%v: Character got %v, expected %v", test.kind, got, test.character)
+		}
+		if got := test.kind.ByteOffset(line, test.character); got != offset {
+			t.Errorf("%v: ByteOffset got %v, expected %v", test.kind, got, offset)
+		}
+		if got := test.kind.Len(line[:offset]); got != test.character {
+			t.Errorf("%v: Len got %v, expected %v", test.kind, got, test.character)
+		}
+	}
+}
+
+func TestPositionEncodingInvalidUTF8(t *testing.T) {
+	line := "a\xffb\xe2\x82 = c"
+	for _, test := range []struct {
+		kind      PositionEncodingKind
+		character uint32
+	}{
+		{PositionEncodingKindUTF8, 5},
+		{PositionEncodingKindUTF16, 5},
+		{PositionEncodingKindUTF32, 5},
+	} {
+		offset := len("a\xffb\xe2\x82")
+		if got := test.kind.Character(line, offset); got != test.character {
+			t.Errorf("%v: Character got %v, expected %v", test.kind, got, test.character)
+		}
+		if got := test.kind.ByteOffset(line, test.character); got != offset {
+			t.Errorf("%v: ByteOffset got %v, expected %v", test.kind, got, offset)
+		}
+		if got := test.kind.Len(line[:offset]); got != test.character {
+			t.Errorf("%v: Len got %v, expected %v", test.kind, got, test.character)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package lsp
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestProtocolRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		value any
+		json  string
+	}{
+		{&DocumentSymbol{}, `{"name":"a","kind":20,"range":{"start":{"line":0,"character":0},"end":{"line":2,"character":3}},"selectionRange":{"start":{"line":0,"character":0},"end":{"line":0,"character":1}},"children":[{"name":"b","kind":15,"range":{"start":{"line":1,"character":2},"end":{"line":1,"character":6}},"selectionRange":{"start":{"line":1,"character":2},"end":{"line":1,"character":3}}}]}`},
+		{&FoldingRange{}, `{"startLine":1,"startCharacter":0,"endLine":4,"kind":"comment"}`},
+		{&DocumentFormattingParams{}, `{"workDoneToken":"t","textDocument":{"uri":"file:///a.conl"},"options":{"tabSize":2,"insertSpaces":true,"trimTrailingWhitespace":true}}`},
+		{&RelatedFullDocumentDiagnosticReport{}, `{"kind":"full","resultId":"1","items":[{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":1}},"severity":1,"message":"oops"}],"relatedDocuments":{"file:///b.conl":{"kind":"unchanged","resultId":"2"}}}`},
+		{&DocumentSymbolClientCapabilities{}, `{"symbolKind":{"valueSet":[1,2,3]},"hierarchicalDocumentSymbolSupport":true}`},
+		{&SymbolInformation{}, `{"name":"a","kind":20,"containerName":"b","location":{"uri":"file:///a.conl","range":{"start":{"line":0,"character":0},"end":{"line":0,"character":1}}}}`},
+		{&InitializeParams{}, `{"processId":1,"clientInfo":{"name":"zed"},"rootUri":null,"capabilities":{"general":{"positionEncodings":["utf-8"]}},"trace":"off","workspaceFolders":[{"uri":"file:///a","name":"a"}]}`},
+		{&DidChangeTextDocumentParams{}, `{"textDocument":{"uri":"file:///a.conl","version":2},"contentChanges":[{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":1}},"text":"b"},{"text":"a = b\n"}]}`},
+		{&CompletionParams{}, `{"textDocument":{"uri":"file:///a.conl"},"position":{"line":1,"character":2},"context":{"triggerKind":2,"triggerCharacter":"="}}`},
+		{&InitializeResult{}, `{"capabilities":{"positionEncoding":"utf-8","textDocumentSync":2,"hoverProvider":true,"diagnosticProvider":{"interFileDependencies":true,"workspaceDiagnostics":true}},"serverInfo":{"name":"conl-lsp"}}`},
+		{&InitializeResult{}, `{"capabilities":{"textDocumentSync":{"openClose":true,"save":{"includeText":true}},"hoverProvider":{"workDoneProgress":true},"declarationProvider":{"documentSelector":null}}}`},
+		{&Hover{}, `{"contents":["a",{"language":"conl","value":"b = c"}]}`},
+		{&CompletionItem{}, `{"label":"a","documentation":"docs","textEdit":{"newText":"a","insert":{"start":{"line":0,"character":0},"end":{"line":0,"character":1}},"replace":{"start":{"line":0,"character":0},"end":{"line":0,"character":2}}}}`},
+		{&WorkspaceEdit{}, `{"documentChanges":[{"kind":"create","uri":"file:///b.conl"},{"textDocument":{"uri":"file:///b.conl","version":null},"edits":[{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"newText":"a","annotationId":"x"}]},{"kind":"delete","uri":"file:///c.conl"}]}`},
+	} {
+		if err := json.Unmarshal([]byte(test.json), test.value); err != nil {
+			t.Fatalf("%T: %v", test.value, err)
+		}
+		raw, err := json.Marshal(test.value)
+		if err != nil {
+			t.Fatalf("%T: %v", test.value, err)
+		}
+		var expected, got any
+		json.Unmarshal([]byte(test.json), &expected)
+		json.Unmarshal(raw, &got)
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("%T: got %s, expected %s", test.value, raw, test.json)
+		}
+	}
+}
+
+func TestUnions(t *testing.T) {
+	hover := &Hover{}
+	if err := json.Unmarshal([]byte(`{"contents":{"kind":"markdown","value":"a"}}`), hover); err != nil {
+		t.Fatal(err)
+	}
+	if expected := (&Hover{Contents: &MarkupContent{Kind: MarkupKindMarkdown, Value: "a"}}); !reflect.DeepEqual(hover, expected) {
+		t.Fatalf("got %#v, expected %#v", hover, expected)
+	}
+
+	edit := &WorkspaceEdit{}
+	if err := json.Unmarshal([]byte(`{"documentChanges":[{"kind":"rename","oldUri":"file:///a.conl","newUri":"file:///b.conl"},{"kind":"delete","uri":"file:///c.conl"}]}`), edit); err != nil {
+		t.Fatal(err)
+	}
+	expected := []TextDocumentEditOrCreateFileOrRenameFileOrDeleteFile{
+		&RenameFile{Kind: "rename", OldURI: "file:///a.conl", NewURI: "file:///b.conl"},
+		&DeleteFile{Kind: "delete", URI: "file:///c.conl"},
+	}
+	if !reflect.DeepEqual(edit.DocumentChanges, expected) {
+		t.Fatalf("got %#v, expected %#v", edit.DocumentChanges, expected)
+	}
+
+	capabilities := &ServerCapabilities{}
+	if err := json.Unmarshal([]byte(`{"hoverProvider":"yes"}`), capabilities); err == nil {
+		t.Fatalf("expected an error, got %#v", capabilities.HoverProvider)
+	}
+	if _, err := json.Marshal(ServerCapabilities{HoverProvider: "yes"}); err == nil {
+		t.Fatal("expected an error marshaling a string as hoverProvider")
+	}
+}
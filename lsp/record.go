@@ -0,0 +1,68 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"iter"
+	"sync"
+	"time"
+)
+
+// A RecordedFrame is one line of a session recording.
+// Direction is "recv" for frames sent by the client, and "send" for frames
+// sent by the server.
+type RecordedFrame struct {
+	Time      time.Time `json:"time"`
+	Direction string    `json:"direction"`
+	Frame     *Frame    `json:"frame"`
+}
+
+type recorder struct {
+	mutex sync.Mutex
+	enc   *json.Encoder
+}
+
+// Record writes every frame received or sent by the connection to w
+// as a line of JSON, so that the session can be replayed later.
+func (c *Connection) Record(w io.Writer) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.recorder = &recorder{enc: json.NewEncoder(w)}
+}
+
+func (c *Connection) record(direction string, frame *Frame) {
+	c.mutex.Lock()
+	r := c.recorder
+	c.mutex.Unlock()
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.enc.Encode(&RecordedFrame{Time: time.Now(), Direction: direction, Frame: frame})
+}
+
+// ReadRecording reads a session written by Connection.Record.
+func ReadRecording(r io.Reader) iter.Seq2[*RecordedFrame, error] {
+	return func(yield func(*RecordedFrame, error) bool) {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, 64*1024*1024)
+		for scanner.Scan() {
+			if len(scanner.Bytes()) == 0 {
+				continue
+			}
+			recorded := &RecordedFrame{}
+			if err := json.Unmarshal(scanner.Bytes(), recorded); err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(recorded, nil) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
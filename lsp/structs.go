@@ -6,58 +6,6 @@ import (
 	"net/url"
 )
 
-// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#initializeParams
-type InitializeParams struct {
-}
-
-// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#initializedParams
-type InitializedParams struct {
-}
-
-// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#initializeResult
-type InitializeResult struct {
-	Capabilities ServerCapabilities `json:"capabilities"`
-	ServerInfo   *ServerInfo        `json:"serverInfo,omitempty"`
-}
-
-// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#initializeResult
-type ServerInfo struct {
-	Name    string `json:"name"`
-	Version string `json:"version,omitempty"`
-}
-
-// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#serverCapabilities
-type ServerCapabilities struct {
-	PositionEncodingKind PositionEncodingKind `json:"positionEncodingKind"`
-	TextDocumentSync     TextDocumentSyncKind `json:"textDocumentSync"`
-	CompletionProvider   *CompletionOptions   `json:"completionProvider,omitempty"`
-	HoverProvider        bool                 `json:"hoverProvider,omitempty"`
-}
-
-// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#completionOptions
-type CompletionOptions struct {
-	TriggerCharacters []string `json:"triggerCharacters,omitempty"`
-	ResolveProvider   bool     `json:"resolveProvider,omitempty"`
-}
-
-// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#positionEncodingKind
-type PositionEncodingKind string
-
-const (
-	PositionEncodingUTF8  PositionEncodingKind = "utf-8"
-	PositionEncodingUTF16 PositionEncodingKind = "utf-16"
-	PositionEncodingUTF32 PositionEncodingKind = "utf-32"
-)
-
-// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocumentSyncKind
-type TextDocumentSyncKind int
-
-const (
-	TextDocumentSyncNone        TextDocumentSyncKind = 0
-	TextDocumentSyncFull        TextDocumentSyncKind = 1
-	TextDocumentSyncIncremental TextDocumentSyncKind = 2
-)
-
 // Many messages, notifications and responses expect no parameter or value
 // use Null to indicate this.
 type Null struct {
@@ -67,24 +15,6 @@ func (n *Null) MarshalJSON() ([]byte, error) {
 	return []byte("null"), nil
 }
 
-// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_didOpen
-type DidOpenTextDocumentParams struct {
-	TextDocument TextDocumentItem `json:"textDocument"`
-}
-
-// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_didClose
-type DidCloseTextDocumentParams struct {
-	TextDocument TextDocumentItem `json:"textDocument"`
-}
-
-// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocumentItem
-type TextDocumentItem struct {
-	URI        DocumentURI `json:"uri"`
-	LanguageID string      `json:"languageId"`
-	Version    int32       `json:"version"`
-	Text       string      `json:"text"`
-}
-
 // https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#uri
 type DocumentURI string
 
@@ -126,132 +56,3 @@ func (d DocumentURI) ResolveReference(requested string) (DocumentURI, error) {
 	resultUrl := base.ResolveReference(relative)
 	return DocumentURI(resultUrl.String()), nil
 }
-
-// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#didChangeTextDocumentParams
-type DidChangeTextDocumentParams struct {
-	TextDocument   VersionedTextDocumentIdentifier  `json:"textDocument"`
-	ContentChanges []TextDocumentContentChangeEvent `json:"contentChanges"`
-}
-
-type VersionedTextDocumentIdentifier struct {
-	URI     DocumentURI `json:"uri"`
-	Version int32       `json:"version"`
-}
-
-type TextDocumentIdentifier struct {
-	URI DocumentURI `json:"uri"`
-}
-
-// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocumentContentChangeEvent
-type TextDocumentContentChangeEvent struct {
-	Range *Range `json:"range,omitempty"`
-	Text  string `json:"text"`
-}
-
-// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#range
-type Range struct {
-	Start Position `json:"start"`
-	End   Position `json:"end"`
-}
-
-// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#position
-type Position struct {
-	Line      uint32 `json:"line"`
-	Character uint32 `json:"character"`
-}
-
-type ShowMessageParams struct {
-	Type    MessageType `json:"type"`
-	Message string      `json:"message"`
-}
-
-type MessageType int
-
-const (
-	MessageTypeError   MessageType = 1
-	MessageTypeWarning MessageType = 2
-	MessageTypeInfo    MessageType = 3
-	MessageTypeLog     MessageType = 4
-	MessageTypeDebug   MessageType = 5
-)
-
-// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#publishDiagnosticsParams
-type PublishDiagnosticsParams struct {
-	URI         DocumentURI   `json:"uri"`
-	Version     int32         `json:"version"`
-	Diagnostics []*Diagnostic `json:"diagnostics"`
-}
-
-// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#diagnostic
-type Diagnostic struct {
-	Range    Range              `json:"range"`
-	Severity DiagnosticSeverity `json:"severity"`
-	Message  string             `json:"message"`
-}
-
-// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#diagnosticSeverity
-type DiagnosticSeverity int
-
-const (
-	DiagnosticSeverityError       DiagnosticSeverity = 1
-	DiagnosticSeverityWarning     DiagnosticSeverity = 2
-	DiagnosticSeverityInformation DiagnosticSeverity = 3
-	DiagnosticSeverityHint        DiagnosticSeverity = 4
-)
-
-// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_completion
-type CompletionParams struct {
-	TextDocument TextDocumentIdentifier `json:"textDocument"`
-	Position     Position               `json:"position"`
-}
-
-// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#completionList
-type CompletionList struct {
-	IsIncomplete bool              `json:"isIncomplete"`
-	Items        []*CompletionItem `json:"items"`
-}
-
-type CompletionItem struct {
-	Label          string         `json:"label"`
-	InsertText     string         `json:"insertText,omitempty"`
-	TextEdit       *TextEdit      `json:"textEdit,omitempty"`
-	Documentation  *MarkupContent `json:"documentation,omitempty"`
-	InsertTextMode InsertTextMode `json:"insertTextMode,omitempty"`
-}
-
-type InsertTextMode int
-
-const (
-	InsertTextModeAsIs              InsertTextMode = 1
-	InsertTextModeAdjustIndentation InsertTextMode = 2
-)
-
-type TextEdit struct {
-	Range   Range  `json:"range"`
-	NewText string `json:"newText"`
-}
-
-type MarkupContent struct {
-	Kind  MarkupKind `json:"kind"`
-	Value string     `json:"value"`
-}
-
-type MarkupKind string
-
-const (
-	MarkupKindPlainText MarkupKind = "plaintext"
-	MarkupKindMarkdown  MarkupKind = "markdown"
-)
-
-type HoverParams struct {
-	TextDocument TextDocumentIdentifier `json:"textDocument"`
-	Position     Position               `json:"position"`
-}
-
-type Hover struct {
-	Contents *MarkupContent `json:"contents"`
-}
-
-type DocumentFormattingParams struct {
-	TextDocument TextDocumentIdentifier `json:"textDocument"`
-}
@@ -0,0 +1,137 @@
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// unionMember is one of the types that a union-typed field can hold.
+// Structures are held as pointers, and other types as values.
+type unionMember struct {
+	// value is a pointer to the zero value of the type
+	value any
+	// required are the properties a JSON object must have to be a value of a
+	// structure, and literals the values of those that are string literals.
+	required []string
+	literals map[string]string
+}
+
+var rawMessageType = reflect.TypeFor[json.RawMessage]()
+
+// held returns the type of the values that a union holds for m.
+func (m *unionMember) held() reflect.Type {
+	t := reflect.TypeOf(m.value)
+	if t.Elem().Kind() == reflect.Struct {
+		return t
+	}
+	return t.Elem()
+}
+
+// matches reports whether data, a JSON value that is not null, can be
+// decoded as m. Members that are json.RawMessage match anything.
+func (m *unionMember) matches(data []byte, object map[string]json.RawMessage) bool {
+	t := reflect.TypeOf(m.value).Elem()
+	if t == rawMessageType {
+		return true
+	}
+	switch data[0] {
+	case 't', 'f':
+		return t.Kind() == reflect.Bool
+	case '"':
+		return t.Kind() == reflect.String
+	case '[':
+		return t.Kind() == reflect.Slice
+	case '{':
+		if t.Kind() == reflect.Map {
+			return true
+		}
+		if t.Kind() != reflect.Struct {
+			return false
+		}
+		for _, name := range m.required {
+			if _, ok := object[name]; !ok {
+				return false
+			}
+		}
+		for name, expected := range m.literals {
+			var value string
+			if json.Unmarshal(object[name], &value) != nil || value != expected {
+				return false
+			}
+		}
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// unmarshalUnion decodes data, the value of the union-typed property named
+// property, as whichever of members it matches. json.RawMessage members
+// are only used if no other member matches, and if more than one structure
+// matches the one with the most required properties is used.
+func unmarshalUnion(property string, data json.RawMessage, members []unionMember) (any, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var object map[string]json.RawMessage
+	if data[0] == '{' {
+		if err := json.Unmarshal(data, &object); err != nil {
+			return nil, err
+		}
+	}
+	var match, fallback *unionMember
+	for i := range members {
+		m := &members[i]
+		if !m.matches(data, object) {
+			continue
+		}
+		if reflect.TypeOf(m.value).Elem() == rawMessageType {
+			if fallback == nil {
+				fallback = m
+			}
+		} else if match == nil || len(m.required) > len(match.required) {
+			match = m
+		}
+	}
+	if match == nil {
+		match = fallback
+	}
+	if match == nil {
+		return nil, fmt.Errorf("cannot unmarshal %s into %s: expected %s", data, property, unionTypes(members))
+	}
+	value := reflect.New(reflect.TypeOf(match.value).Elem())
+	if err := json.Unmarshal(data, value.Interface()); err != nil {
+		return nil, err
+	}
+	if match.held().Kind() == reflect.Pointer {
+		return value.Interface(), nil
+	}
+	return value.Elem().Interface(), nil
+}
+
+// checkUnion returns an error if v, the value of the union-typed property
+// named property, is not nil or one of the types of members.
+func checkUnion(property string, v any, members []unionMember) error {
+	if v == nil {
+		return nil
+	}
+	for i := range members {
+		if reflect.TypeOf(v) == members[i].held() {
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot marshal %T as %s: expected %s", v, property, unionTypes(members))
+}
+
+func unionTypes(members []unionMember) string {
+	types := []string{}
+	for i := range members {
+		types = append(types, members[i].held().String())
+	}
+	return strings.Join(types, " or ")
+}
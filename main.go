@@ -4,50 +4,116 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"runtime/debug"
 
 	"github.com/ConradIrwin/conl-lsp/lsp"
 )
 
-var log *os.File
-
-func logPanic() {
+func logPanic(logger *slog.Logger) {
 	if r := recover(); r != nil {
-		log.WriteString(fmt.Sprintf("%#v", r))
-		log.WriteString(string(debug.Stack()))
+		logger.Error("panic", "error", fmt.Sprintf("%#v", r), "stack", string(debug.Stack()))
 	}
 }
 
 func main() {
-	logFile := flag.String("log", "", "a file to log to")
-	verbose := flag.Bool("verbose", false, "whether to log raw messages")
+	os.Exit(run())
+}
+
+// run serves the language server, and returns the exit code: 0 if the
+// client sent shutdown before exit, and 1 otherwise.
+func run() int {
+	logFile := flag.String("log", "", "a file to log to (defaults to stderr)")
+	logLevel := flag.String("log-level", "info", "the minimum level to log: debug, info, warn or error")
+	verbose := flag.Bool("verbose", false, "whether to log raw messages (the same as --log-level=debug)")
+	record := flag.String("record", "", "a file to record the session to, for use with the replay command (not with --listen)")
+	listenAddr := flag.String("listen", "", "serve clients on tcp://host:port, unix:///path or ws://host:port/path instead of stdio")
+	allowedOrigins := []string{}
+	flag.Func("allow-origin", "a web page origin (such as https://example.com) allowed to connect to a ws:// server; may be repeated", func(origin string) error {
+		allowedOrigins = append(allowedOrigins, origin)
+		return nil
+	})
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags]\n       %s [flags] replay <recording.jsonl>\n", os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(*logLevel)); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --log-level: %v\n", err)
+		return 2
+	}
+	if *verbose {
+		level = slog.LevelDebug
+	}
+
+	var output io.Writer = os.Stderr
 	if logFile != nil && *logFile != "" {
-		var err error
-		log, err = os.Create(*logFile)
+		f, err := os.Create(*logFile)
 		if err != nil {
 			panic(err)
 		}
-		defer log.Close()
-		if *verbose {
-			lsp.FrameLogger = func(prefix string, data []byte) {
-				log.WriteString(prefix + ": " + string(data) + "\n")
-			}
+		defer f.Close()
+		output = f
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(output, &slog.HandlerOptions{Level: level})))
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("panic", "error", fmt.Sprintf("%#v", r), "stack", string(debug.Stack()))
+			panic(r)
+		}
+	}()
+
+	if flag.Arg(0) == "replay" {
+		if flag.NArg() != 2 {
+			flag.Usage()
+			return 2
+		}
+		diffs, err := replay(flag.Arg(1), os.Stdout)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
 		}
-		defer func() {
-			if r := recover(); r != nil {
-				log.WriteString(fmt.Sprintf("%#v", r))
-				log.WriteString(string(debug.Stack()))
-				panic(r)
-			}
-		}()
+		if diffs > 0 {
+			fmt.Printf("%d differences\n", diffs)
+			return 1
+		}
+		return 0
+	}
+
+	if *listenAddr != "" {
+		// a recording replays a single session, so there is nowhere to
+		// record the sessions of several clients.
+		if *record != "" {
+			fmt.Fprintln(os.Stderr, "--record cannot be used with --listen")
+			return 2
+		}
+		if err := listen(context.Background(), *listenAddr, allowedOrigins); err != nil {
+			panic(err)
+		}
+		return 0
 	}
 
 	c := lsp.NewConnection()
-	err := NewServer(c).Serve(context.Background(), os.Stdin, os.Stdout)
+	if *record != "" {
+		f, err := os.Create(*record)
+		if err != nil {
+			panic(err)
+		}
+		defer f.Close()
+		c.Record(f)
+	}
+	server := NewServer(c)
+	server.watchClient = true
+	err := server.Serve(context.Background(), os.Stdin, os.Stdout)
 	if err != nil {
 		panic(err)
 	}
+	if !c.ShutdownRequested() {
+		return 1
+	}
+	return 0
 }
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"time"
+)
+
+// parentPollInterval is how often the client process is checked.
+var parentPollInterval = 3 * time.Second
+
+// watchParent exits the server if the client process with the given id
+// stops running, so that servers are not orphaned when an editor crashes.
+func (s *Server) watchParent(pid int) {
+	ticker := time.NewTicker(parentPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			if !processExists(pid) {
+				s.logger.Info("client process exited", "pid", pid)
+				s.c.Exit()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix && !windows
+
+package main
+
+// processExists reports whether the process with the given id is running.
+// On this platform there is no way to tell, so it is assumed to be.
+func processExists(pid int) bool {
+	return true
+}
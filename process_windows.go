@@ -0,0 +1,29 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"syscall"
+)
+
+// https://learn.microsoft.com/en-us/windows/win32/api/processthreadsapi/nf-processthreadsapi-getexitcodeprocess
+const stillActive = 259
+
+// https://learn.microsoft.com/en-us/windows/win32/procthread/process-security-and-access-rights
+// (unlike PROCESS_QUERY_INFORMATION, this is granted for elevated processes)
+const processQueryLimitedInformation = 0x1000
+
+// processExists reports whether the process with the given id is running.
+func processExists(pid int) bool {
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return errors.Is(err, syscall.ERROR_ACCESS_DENIED)
+	}
+	defer syscall.CloseHandle(h)
+	var code uint32
+	if err := syscall.GetExitCodeProcess(h, &code); err != nil {
+		return true
+	}
+	return code == stillActive
+}
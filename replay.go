@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ConradIrwin/conl-lsp/lsp"
+)
+
+// replayTimeout is how long replay waits for each response from the server.
+var replayTimeout = 10 * time.Second
+
+// replayOutput collects the frames sent by the server during a replay.
+type replayOutput struct {
+	mutex   sync.Mutex
+	frames  []*lsp.Frame
+	changed chan struct{}
+	closed  bool
+}
+
+func (o *replayOutput) collect(r io.Reader) {
+	for frame, err := range lsp.ReadFrames(r) {
+		if err != nil {
+			break
+		}
+		o.mutex.Lock()
+		if frame.Batch != nil {
+			o.frames = append(o.frames, frame.Batch...)
+		} else {
+			o.frames = append(o.frames, frame)
+		}
+		close(o.changed)
+		o.changed = make(chan struct{})
+		o.mutex.Unlock()
+	}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.closed = true
+	close(o.changed)
+}
+
+// waitFor waits until the server has sent a frame that matches,
+// and returns false if it does not do so within replayTimeout.
+func (o *replayOutput) waitFor(match func(*lsp.Frame) bool) bool {
+	timeout := time.After(replayTimeout)
+	for {
+		o.mutex.Lock()
+		found := slices.ContainsFunc(o.frames, match)
+		closed, changed := o.closed, o.changed
+		o.mutex.Unlock()
+		if found {
+			return true
+		}
+		if closed {
+			return false
+		}
+		select {
+		case <-changed:
+		case <-timeout:
+			return false
+		}
+	}
+}
+
+func isRequest(id json.RawMessage) func(*lsp.Frame) bool {
+	return func(f *lsp.Frame) bool {
+		return f.Method != "" && string(f.Id) == string(id)
+	}
+}
+
+func isResponse(id json.RawMessage) func(*lsp.Frame) bool {
+	return func(f *lsp.Frame) bool {
+		return f.Method == "" && string(f.Id) == string(id)
+	}
+}
+
+// replay drives a new Server with the frames the client sent in a recording
+// made with --record, and reports to w any differences between what the
+// server sent then and what it sends now. It returns the number of differences.
+func replay(path string, w io.Writer) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	recorded := []*lsp.RecordedFrame{}
+	for frame, err := range lsp.ReadRecording(f) {
+		if err != nil {
+			return 0, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		recorded = append(recorded, frame)
+	}
+
+	readIn, writeIn := io.Pipe()
+	readOut, writeOut := io.Pipe()
+	// the recorded client's processId is not watched, as it is no longer running
+	server := NewServer(lsp.NewConnection())
+	go func() {
+		defer logPanic(server.logger)
+		defer readIn.Close()
+		if err := server.Serve(context.Background(), readIn, writeOut); err != nil {
+			writeOut.CloseWithError(err)
+		}
+	}()
+	output := &replayOutput{changed: make(chan struct{})}
+	go output.collect(readOut)
+
+	input := make(chan *lsp.Frame)
+	written := make(chan struct{})
+	go func() {
+		lsp.WriteFrames(context.Background(), writeIn, input)
+		writeIn.Close()
+		close(written)
+	}()
+
+	methods := map[string]string{}
+	for _, r := range recorded {
+		if r.Direction != "recv" {
+			continue
+		}
+		frames := []*lsp.Frame{r.Frame}
+		if r.Frame.Batch != nil {
+			frames = r.Frame.Batch
+		}
+		for _, frame := range frames {
+			if frame.Method == "" && frame.Id != nil {
+				output.waitFor(isRequest(frame.Id))
+			} else if frame.Id != nil {
+				methods[string(frame.Id)] = frame.Method
+			}
+		}
+		input <- r.Frame
+		for _, frame := range frames {
+			if frame.Method != "" && frame.Id != nil {
+				if !output.waitFor(isResponse(frame.Id)) {
+					fmt.Fprintf(w, "no response to %s (%s)\n", frame.Method, frame.Id)
+				}
+			}
+		}
+	}
+	close(input)
+	<-written
+	output.waitFor(func(*lsp.Frame) bool { return false })
+
+	sent := []*lsp.Frame{}
+	for _, r := range recorded {
+		if r.Direction != "send" {
+			continue
+		}
+		if r.Frame.Batch != nil {
+			sent = append(sent, r.Frame.Batch...)
+		} else {
+			sent = append(sent, r.Frame)
+		}
+	}
+	output.mutex.Lock()
+	defer output.mutex.Unlock()
+	return diffSessions(w, methods, sent, output.frames), nil
+}
+
+// diffSessions compares the frames sent by the server in two sessions.
+// Responses and server requests are matched by id. For diagnostics only the
+// last set published for each document is compared, as intermediate results
+// depend on timing. Other notifications are compared in order per method.
+func diffSessions(w io.Writer, methods map[string]string, expected []*lsp.Frame, actual []*lsp.Frame) int {
+	type summary struct {
+		keys   []string
+		frames map[string]*lsp.Frame
+	}
+	summarize := func(frames []*lsp.Frame) *summary {
+		s := &summary{frames: map[string]*lsp.Frame{}}
+		counts := map[string]int{}
+		for _, f := range frames {
+			var key string
+			switch {
+			case f.Method == "$/logTrace" || f.Method == "window/logMessage":
+				continue
+			case f.Method == "":
+				key = fmt.Sprintf("response to %s (%s)", methods[string(f.Id)], f.Id)
+			case f.Id != nil:
+				key = fmt.Sprintf("request %s (%s)", f.Method, f.Id)
+			case f.Method == "textDocument/publishDiagnostics":
+				params := lsp.PublishDiagnosticsParams{}
+				json.Unmarshal(f.Params, &params)
+				key = fmt.Sprintf("diagnostics for %s", params.URI)
+			default:
+				key = fmt.Sprintf("%s #%d", f.Method, counts[f.Method])
+				counts[f.Method]++
+			}
+			if _, ok := s.frames[key]; !ok {
+				s.keys = append(s.keys, key)
+			}
+			s.frames[key] = f
+		}
+		return s
+	}
+
+	before, after := summarize(expected), summarize(actual)
+	diffs := 0
+	for _, key := range before.keys {
+		b, a := comparableFrame(before.frames[key]), comparableFrame(after.frames[key])
+		if !reflect.DeepEqual(b, a) {
+			diffs++
+			fmt.Fprintf(w, "%s differs:\n- %s\n+ %s\n", key, mustJSON(b), mustJSON(a))
+		}
+	}
+	for _, key := range after.keys {
+		if _, ok := before.frames[key]; !ok {
+			diffs++
+			fmt.Fprintf(w, "unexpected %s:\n+ %s\n", key, mustJSON(comparableFrame(after.frames[key])))
+		}
+	}
+	return diffs
+}
+
+// comparableFrame decodes the parts of a frame that should be the same in both
+// sessions. The server's version is ignored so that recordings made with a
+// release build can be replayed against a development build.
+func comparableFrame(f *lsp.Frame) any {
+	if f == nil {
+		return nil
+	}
+	result := map[string]any{}
+	for key, raw := range map[string]json.RawMessage{"params": f.Params, "result": f.Result} {
+		if raw == nil {
+			continue
+		}
+		var v any
+		json.Unmarshal(raw, &v)
+		if m, ok := v.(map[string]any); ok {
+			delete(m, "serverInfo")
+		}
+		result[key] = v
+	}
+	if f.Error != nil {
+		result["error"] = f.Error.Message
+	}
+	return result
+}
+
+func mustJSON(v any) string {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(raw)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ConradIrwin/conl-lsp/lsp"
+)
+
+func recordSession(t *testing.T, path string) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	c := lsp.NewConnection()
+	c.Record(f)
+	server := newTestServerOn(t, c)
+	testRequest[lsp.InitializeResult](server, "initialize", lsp.InitializeParams{})
+	uri := lsp.DocumentURI("file:///tmp/replay.conl")
+	testNotify(server, "textDocument/didOpen", lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: uri, LanguageID: "conl", Version: 1, Text: "a = 1\n"},
+	})
+	testRequest[lsp.Hover](server, "textDocument/hover", lsp.HoverParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+			Position:     lsp.Position{Line: 0, Character: 0},
+		},
+	})
+	testRequest[lsp.Null](server, "shutdown", nil)
+	testNotify(server, "exit", nil)
+	for {
+		if _, _, ok := server.readFrame(); !ok {
+			break
+		}
+	}
+}
+
+func TestReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	recordSession(t, path)
+
+	out := &bytes.Buffer{}
+	diffs, err := replay(path, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diffs != 0 {
+		t.Fatalf("expected replay to match, got:\n%s", out)
+	}
+
+	recording, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(recording)), "\n")
+	hoverId := ""
+	for i, line := range lines {
+		recorded := lsp.RecordedFrame{}
+		if err := json.Unmarshal([]byte(line), &recorded); err != nil {
+			t.Fatal(err)
+		}
+		if recorded.Frame.Method == "textDocument/hover" {
+			hoverId = string(recorded.Frame.Id)
+		}
+		if recorded.Direction == "send" && recorded.Frame.Method == "" && string(recorded.Frame.Id) == hoverId {
+			recorded.Frame.Result = json.RawMessage(`{"contents":{"kind":"markdown","value":"changed"}}`)
+			changed, _ := json.Marshal(recorded)
+			lines[i] = string(changed)
+		}
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out.Reset()
+	diffs, err = replay(path, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diffs != 1 || !strings.Contains(out.String(), "response to textDocument/hover") {
+		t.Fatalf("expected replay to report the changed hover, got:\n%s", out)
+	}
+}
@@ -5,15 +5,19 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"maps"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime/debug"
+	"slices"
 	"strings"
 	"sync"
-	"unicode/utf16"
+	"sync/atomic"
+	"time"
 
 	"github.com/ConradIrwin/conl-go"
 	"github.com/ConradIrwin/conl-go/schema"
@@ -27,20 +31,55 @@ type httpSchema struct {
 
 type Server struct {
 	c           *lsp.Connection
+	logger      *slog.Logger
+	done        <-chan struct{}
 	mutex       sync.RWMutex
-	openDocs    map[lsp.DocumentURI]*TextDocument
+	client      *lsp.InitializeParams
+	encoding    lsp.PositionEncodingKind
 	httpSchemas map[lsp.DocumentURI]httpSchema
-
-	schemasInUse map[lsp.DocumentURI]lsp.DocumentURI
+	current     atomic.Pointer[snapshot]
+
+	// parsedSchemas, analyses and fileReports cache work shared between
+	// requests, and are invalidated by version (see analyze and fileReport).
+	parsedSchemas map[lsp.DocumentURI]parsedSchema
+	analyses      map[lsp.DocumentURI]*analysis
+	fileReports   map[lsp.DocumentURI]*fileReport
+
+	diagnostics *diagnosticsScheduler
+	index       *workspaceIndex
+	// pull is true if the client requests diagnostics with textDocument/diagnostic,
+	// in which case they are not published.
+	pull    bool
+	refresh *time.Timer
+	// changed is closed (and replaced) whenever a document changes, to wake
+	// workspace/diagnostic requests that are waiting for one.
+	changed chan struct{}
+	// watchClient is set when serving over stdio, where the processId sent
+	// by the client is a local process. Over a socket (or in a replay) the
+	// client may be in another pid namespace, or no longer running.
+	watchClient bool
 }
 
 func NewServer(c *lsp.Connection) *Server {
 	s := &Server{c: c,
-		openDocs:     make(map[lsp.DocumentURI]*TextDocument),
-		schemasInUse: map[lsp.DocumentURI]lsp.DocumentURI{},
-		httpSchemas:  map[lsp.DocumentURI]httpSchema{},
+		logger:      c.Logger(),
+		client:      &lsp.InitializeParams{},
+		encoding:    lsp.PositionEncodingKindUTF16,
+		httpSchemas: map[lsp.DocumentURI]httpSchema{},
+
+		parsedSchemas: map[lsp.DocumentURI]parsedSchema{},
+		analyses:      map[lsp.DocumentURI]*analysis{},
+		index:         newWorkspaceIndex(),
+		changed:       make(chan struct{}),
 	}
+	s.current.Store(&snapshot{
+		docs:         map[lsp.DocumentURI]*TextDocument{},
+		schemasInUse: map[lsp.DocumentURI]lsp.DocumentURI{},
+	})
+	s.diagnostics = newDiagnosticsScheduler(s.logger, s.validate, s.PublishDiagnostics)
+	c.Use(lsp.LogCalls(s.logger))
 	lsp.HandleRequest(c, "initialize", s.initialize)
+	lsp.HandleNotification(c, "initialized", s.initialized)
 	lsp.HandleRequest(c, "shutdown", s.shutdown)
 	lsp.HandleNotification(c, "exit", s.exit)
 
@@ -49,10 +88,22 @@ func NewServer(c *lsp.Connection) *Server {
 	lsp.HandleNotification(c, "textDocument/didOpen", s.textDocumentDidOpen)
 	lsp.HandleNotification(c, "textDocument/didChange", s.textDocumentDidChange)
 	lsp.HandleNotification(c, "textDocument/didClose", s.textDocumentDidClose)
+	lsp.HandleRequest(c, "textDocument/diagnostic", s.textDocumentDiagnostic)
+	lsp.HandleRequest(c, "workspace/diagnostic", s.workspaceDiagnostic)
+	lsp.HandleRequest(c, "textDocument/formatting", s.textDocumentFormatting)
+	lsp.HandleRequest(c, "textDocument/rangeFormatting", s.textDocumentRangeFormatting)
+	lsp.HandleRequest(c, "textDocument/onTypeFormatting", s.textDocumentOnTypeFormatting)
+	lsp.HandleRequest(c, "textDocument/documentSymbol", s.textDocumentDocumentSymbol)
+	lsp.HandleRequest(c, "workspace/symbol", s.workspaceSymbol)
+	lsp.HandleRequest(c, "textDocument/foldingRange", s.textDocumentFoldingRange)
+	lsp.HandleNotification(c, "workspace/didChangeWatchedFiles", s.workspaceDidChangeWatchedFiles)
 	return s
 }
 
 func (s *Server) Serve(ctx context.Context, r io.Reader, w io.WriteCloser) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	s.done = ctx.Done()
 	return s.c.Serve(ctx, r, w)
 }
 
@@ -61,13 +112,40 @@ func (s *Server) initialize(ctx context.Context, params *lsp.InitializeParams) (
 	if !ok {
 		return nil, errors.New("failed to read build info")
 	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.client = params
+	s.c.SetTrace(params.Trace)
+	if params.ProcessID != nil && s.watchClient {
+		go s.watchParent(int(*params.ProcessID))
+	}
+	if params.Capabilities.General != nil {
+		s.encoding = lsp.NegotiatePositionEncoding(params.Capabilities.General.PositionEncodings)
+	}
+	s.pull = params.Capabilities.TextDocument != nil && params.Capabilities.TextDocument.Diagnostic != nil
+
+	capabilities := lsp.ServerCapabilities{
+		PositionEncoding:                 s.encoding,
+		TextDocumentSync:                 lsp.TextDocumentSyncKindIncremental,
+		CompletionProvider:               &lsp.CompletionOptions{ResolveProvider: false, TriggerCharacters: []string{"=", " "}},
+		HoverProvider:                    true,
+		DocumentFormattingProvider:       true,
+		DocumentRangeFormattingProvider:  true,
+		DocumentOnTypeFormattingProvider: &lsp.DocumentOnTypeFormattingOptions{FirstTriggerCharacter: "\n"},
+		DocumentSymbolProvider:           true,
+		WorkspaceSymbolProvider:          true,
+		FoldingRangeProvider:             true,
+	}
+	if s.pull {
+		capabilities.DiagnosticProvider = &lsp.DiagnosticOptions{
+			InterFileDependencies: true,
+			WorkspaceDiagnostics:  true,
+		}
+	}
+
 	return &lsp.InitializeResult{
-		Capabilities: lsp.ServerCapabilities{
-			PositionEncodingKind: lsp.PositionEncodingUTF16,
-			TextDocumentSync:     lsp.TextDocumentSyncIncremental,
-			CompletionProvider:   &lsp.CompletionOptions{ResolveProvider: false, TriggerCharacters: []string{"=", " "}},
-			HoverProvider:        true,
-		},
+		Capabilities: capabilities,
 		ServerInfo: &lsp.ServerInfo{
 			Name:    "conl-lsp",
 			Version: bi.Main.Version,
@@ -75,8 +153,60 @@ func (s *Server) initialize(ctx context.Context, params *lsp.InitializeParams) (
 	}, nil
 }
 
+func (s *Server) hoverMarkupKind() lsp.MarkupKind {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if caps := s.client.Capabilities.TextDocument; caps != nil && caps.Hover != nil {
+		return preferredMarkupKind(caps.Hover.ContentFormat)
+	}
+	return lsp.MarkupKindMarkdown
+}
+
+func (s *Server) completionMarkupKind() lsp.MarkupKind {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if caps := s.client.Capabilities.TextDocument; caps != nil && caps.Completion != nil && caps.Completion.CompletionItem != nil {
+		return preferredMarkupKind(caps.Completion.CompletionItem.DocumentationFormat)
+	}
+	return lsp.MarkupKindMarkdown
+}
+
+// preferredMarkupKind returns markdown (which schema docs are written in)
+// unless the client has said that it only supports plain text.
+func preferredMarkupKind(supported []lsp.MarkupKind) lsp.MarkupKind {
+	if len(supported) == 0 || slices.Contains(supported, lsp.MarkupKindMarkdown) {
+		return lsp.MarkupKindMarkdown
+	}
+	return lsp.MarkupKindPlainText
+}
+
+// initialized is sent once the client has processed the result of initialize,
+// after which the server may send its own requests to the client.
+func (s *Server) initialized(ctx context.Context, params *lsp.InitializedParams) {
+	go s.indexWorkspace()
+
+	s.mutex.RLock()
+	workspace := s.client.Capabilities.Workspace
+	s.mutex.RUnlock()
+	if workspace != nil && workspace.DidChangeWatchedFiles != nil && workspace.DidChangeWatchedFiles.DynamicRegistration {
+		go s.watchFiles()
+	}
+}
+
+// shutdown waits for any pending diagnostics to be published. The connection
+// rejects any further requests, and the client will then send exit.
 func (s *Server) shutdown(ctx context.Context, params *lsp.Null) (*lsp.Null, error) {
-	return &lsp.Null{}, nil
+	done := make(chan struct{})
+	go func() {
+		s.diagnostics.wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return &lsp.Null{}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 }
 
 func (s *Server) exit(ctx context.Context, params *lsp.Null) {
@@ -86,34 +216,44 @@ func (s *Server) exit(ctx context.Context, params *lsp.Null) {
 func (s *Server) textDocumentDidOpen(ctx context.Context, params *lsp.DidOpenTextDocumentParams) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
-	s.openDocs[params.TextDocument.URI] = NewTextDocument(
+	doc := NewTextDocument(
 		params.TextDocument.URI,
 		params.TextDocument.Version,
 		params.TextDocument.Text,
 		params.TextDocument.LanguageID,
+		s.encoding,
 	)
+	s.update(func(next *snapshot) {
+		next.docs[doc.URI] = doc
+	})
+	s.index.set(doc, true)
+	s.documentsChanged()
 
-	go s.updateDiagnostics(s.openDocs[params.TextDocument.URI])
+	if !s.pull {
+		s.diagnostics.schedule(doc, 0)
+	}
 }
 
 func (s *Server) textDocumentDidClose(ctx context.Context, params *lsp.DidCloseTextDocumentParams) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
-	delete(s.openDocs, params.TextDocument.URI)
-	delete(s.schemasInUse, params.TextDocument.URI)
-
-	s.PublishDiagnostics(&lsp.PublishDiagnosticsParams{
-		URI:         params.TextDocument.URI,
-		Version:     params.TextDocument.Version,
-		Diagnostics: []*lsp.Diagnostic{},
+	s.update(func(next *snapshot) {
+		delete(next.docs, params.TextDocument.URI)
+		delete(next.schemasInUse, params.TextDocument.URI)
 	})
+	delete(s.analyses, params.TextDocument.URI)
+	s.index.close(params.TextDocument.URI)
+	go s.indexFile(params.TextDocument.URI)
+	s.documentsChanged()
+	if !s.pull {
+		s.diagnostics.clear(params.TextDocument.URI)
+	}
 }
 
 func (s *Server) textDocumentDidChange(ctx context.Context, params *lsp.DidChangeTextDocumentParams) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
-	doc, ok := s.openDocs[params.TextDocument.URI]
-
+	doc, ok := s.snapshot().docs[params.TextDocument.URI]
 	if !ok {
 		return
 	}
@@ -121,63 +261,65 @@ func (s *Server) textDocumentDidChange(ctx context.Context, params *lsp.DidChang
 	newDoc.Version = params.TextDocument.Version
 
 	for _, edit := range params.ContentChanges {
-		newDoc.applyChange(edit)
+		if err := newDoc.applyChange(edit); err != nil {
+			s.logger.Warn("invalid change", "uri", params.TextDocument.URI, "version", params.TextDocument.Version, "error", err)
+		}
 	}
-	s.openDocs[params.TextDocument.URI] = newDoc
+	snap := s.update(func(next *snapshot) {
+		next.docs[newDoc.URI] = newDoc
+	})
+	s.index.set(newDoc, true)
+	s.documentsChanged()
 
-	go s.updateDiagnostics(newDoc)
-	for doc, schema := range s.schemasInUse {
-		if schema == params.TextDocument.URI {
-			if doc, ok := s.openDocs[doc]; ok {
-				go s.updateDiagnostics(doc)
-			}
+	if s.pull {
+		if slices.Contains(slices.Collect(maps.Values(snap.schemasInUse)), newDoc.URI) {
+			s.scheduleRefresh()
 		}
+		return
+	}
+	s.diagnostics.schedule(newDoc, diagnosticsDelay)
+	for _, doc := range snap.dependents(newDoc.URI) {
+		s.diagnostics.schedule(doc, diagnosticsDelay)
 	}
 }
 
 func (s *Server) textDocumentCompletion(ctx context.Context, params *lsp.CompletionParams) (*lsp.CompletionList, error) {
-	defer logPanic()
-	doc, ok := s.openDocs[params.TextDocument.URI]
+	snap := s.snapshot()
+	doc, ok := snap.docs[params.TextDocument.URI]
 	if !ok {
 		return nil, fmt.Errorf("document %v not found", params.TextDocument.URI)
 	}
 
-	lines := doc.lines()
-	line := ""
-	if int(params.Position.Line) < len(lines) {
-		line = lines[int(params.Position.Line)]
-	} else {
-		return nil, fmt.Errorf("invalid position: %v >= %v", params.Position.Line, len(lines))
+	if int(params.Position.Line) >= doc.lineCount() {
+		return nil, fmt.Errorf("invalid position: %v >= %v", params.Position.Line, doc.lineCount())
 	}
-	if int(params.Position.Character) < len(line) {
-		line = line[:params.Position.Character]
-	}
-	column := indexUtf16To8(line, params.Position.Character)
+	line := doc.line(int(params.Position.Line))
+	column := doc.Encoding.ByteOffset(line, params.Position.Character)
+	line = line[:column]
 
-	result := schema.Validate([]byte(doc.Content), func(name string) (*schema.Schema, error) {
-		return s.loadSchema(doc.URI, name)
-	})
+	result := s.analyze(ctx, snap, doc).result
 
 	keyStart, keyEnd, _, _, commentStart := schema.SplitLine(line)
 
-	list := &lsp.CompletionList{Items: []*lsp.CompletionItem{}}
+	list := &lsp.CompletionList{Items: []lsp.CompletionItem{}}
+	markupKind := s.completionMarkupKind()
 
 	if column <= keyEnd {
-		lno := getParentLine(lines, int(params.Position.Line))
-		keyStart16 := indexUtf8To16(line, keyStart)
-		keyEnd16 := indexUtf8To16(line, keyEnd)
+		lno := getParentLine(doc, int(params.Position.Line))
+		keyStartChar := doc.Encoding.Character(line, keyStart)
+		keyEndChar := doc.Encoding.Character(line, keyEnd)
 
 		for _, suggestion := range result.SuggestedKeys(lno + 1) {
-			list.Items = append(list.Items, &lsp.CompletionItem{
+			list.Items = append(list.Items, lsp.CompletionItem{
 				Label: suggestion.Value,
 				Documentation: &lsp.MarkupContent{
 					Value: suggestion.Docs,
-					Kind:  lsp.MarkupKindMarkdown,
+					Kind:  markupKind,
 				},
 				TextEdit: &lsp.TextEdit{
 					Range: lsp.Range{
-						Start: lsp.Position{Line: params.Position.Line, Character: keyStart16},
-						End:   lsp.Position{Line: params.Position.Line, Character: keyEnd16},
+						Start: lsp.Position{Line: params.Position.Line, Character: keyStartChar},
+						End:   lsp.Position{Line: params.Position.Line, Character: keyEndChar},
 					},
 					NewText: suggestion.Value,
 				},
@@ -187,17 +329,17 @@ func (s *Server) textDocumentCompletion(ctx context.Context, params *lsp.Complet
 	} else if keyEnd < column && column <= commentStart {
 		values := result.SuggestedValues(int(params.Position.Line) + 1)
 		for _, suggestion := range values {
-			list.Items = append(list.Items, &lsp.CompletionItem{
+			list.Items = append(list.Items, lsp.CompletionItem{
 				Label: suggestion.Value,
 				Documentation: &lsp.MarkupContent{
 					Value: suggestion.Docs,
-					Kind:  lsp.MarkupKindMarkdown,
+					Kind:  markupKind,
 				},
 			})
 		}
 		if strings.HasSuffix(line, "=") {
-			for _, item := range list.Items {
-				item.InsertText = " " + item.Label
+			for i := range list.Items {
+				list.Items[i].InsertText = " " + list.Items[i].Label
 			}
 		}
 	}
@@ -220,18 +362,16 @@ func isInValue(line string, pos int) bool {
 	return pos > eq
 }
 
-func getParentLine(lines []string, lno int) int {
-	line := lines[lno]
+func getParentLine(doc *TextDocument, lno int) int {
+	line := doc.line(lno)
 	p := 0
 	for p < len(line) && (line[p] == ' ' || line[p] == '\t') {
 		p += 1
 	}
 	lno -= 1
 	for lno >= 0 {
-		if len(lines[lno]) < p {
-			continue
-		}
-		prefix := strings.Trim(lines[lno][0:p], " \t")
+		line := doc.line(lno)
+		prefix := strings.Trim(line[:min(p, len(line))], " \t")
 		if prefix != "" && !strings.HasPrefix(prefix, ";") {
 			break
 		}
@@ -255,24 +395,19 @@ func splitLine(line string) (*conl.Token, *conl.Token) {
 }
 
 func (s *Server) textDocumentHover(ctx context.Context, params *lsp.HoverParams) (*lsp.Hover, error) {
-	defer logPanic()
-	doc, ok := s.openDocs[params.TextDocument.URI]
+	snap := s.snapshot()
+	doc, ok := snap.docs[params.TextDocument.URI]
 	if !ok {
 		return nil, fmt.Errorf("document %v not found", params.TextDocument.URI)
 	}
 
-	result := schema.Validate([]byte(doc.Content), func(name string) (*schema.Schema, error) {
-		return s.loadSchema(doc.URI, name)
-	})
+	result := s.analyze(ctx, snap, doc).result
 
-	lines := doc.lines()
-	line := ""
-	if int(params.Position.Line) < len(lines) {
-		line = lines[int(params.Position.Line)]
-	} else {
-		return nil, fmt.Errorf("invalid position: %v >= %v", params.Position.Line, len(lines))
+	if int(params.Position.Line) >= doc.lineCount() {
+		return nil, fmt.Errorf("invalid position: %v >= %v", params.Position.Line, doc.lineCount())
 	}
-	column := indexUtf16To8(line, params.Position.Character)
+	line := doc.line(int(params.Position.Line))
+	column := doc.Encoding.ByteOffset(line, params.Position.Character)
 
 	keyStart, keyEnd, valueStart, valueEnd, _ := schema.SplitLine(line)
 
@@ -285,7 +420,7 @@ func (s *Server) textDocumentHover(ctx context.Context, params *lsp.HoverParams)
 	if docs != "" {
 		return &lsp.Hover{
 			Contents: &lsp.MarkupContent{
-				Kind:  lsp.MarkupKindMarkdown,
+				Kind:  s.hoverMarkupKind(),
 				Value: docs,
 			},
 		}, nil
@@ -311,36 +446,58 @@ func (s *Server) resolveReference(docUrl lsp.DocumentURI, requested string) (lsp
 	return docUrl.ResolveReference(requested)
 }
 
-func (s *Server) loadSchema(docUrl lsp.DocumentURI, requested string) (*schema.Schema, error) {
+// loadSchema loads the schema requested by the document at docUrl, and
+// records that the document uses it if it is open (see recordSchemas for
+// unopened documents). Open schema documents are read from snap, and parsed
+// schemas are cached until the schema changes.
+func (s *Server) loadSchema(ctx context.Context, snap *snapshot, docUrl lsp.DocumentURI, requested string) (*schema.Schema, schemaKey, error) {
+	_, open := snap.docs[docUrl]
 	schemaUrl, err := s.resolveReference(docUrl, requested)
 	if schemaUrl == "" || err != nil {
 		s.mutex.Lock()
 		defer s.mutex.Unlock()
-		delete(s.schemasInUse, docUrl)
+		if _, ok := s.snapshot().schemasInUse[docUrl]; ok && open {
+			s.update(func(next *snapshot) {
+				delete(next.schemasInUse, docUrl)
+			})
+		}
 		if err != nil {
-			return nil, err
+			return nil, schemaKey{}, err
 		}
-		return schema.Any(), nil
+		return schema.Any(), schemaKey{}, nil
 	}
 
 	s.mutex.Lock()
-	shouldLoad := false
-	if s.schemasInUse[docUrl] != schemaUrl {
-		shouldLoad = true
+	shouldLoad := s.snapshot().schemasInUse[docUrl] != schemaUrl
+	if shouldLoad && open {
+		s.update(func(next *snapshot) {
+			next.schemasInUse[docUrl] = schemaUrl
+		})
 	}
-	s.schemasInUse[docUrl] = schemaUrl
 	s.mutex.Unlock()
 
-	if schemaDoc, ok := s.openDocs[schemaUrl]; ok {
-		return schema.Parse([]byte(schemaDoc.Content))
-	}
+	key := schemaKey{uri: schemaUrl, version: s.schemaVersion(snap, schemaUrl)}
 	result := schemaUrl.URL()
-	if result.Scheme == "file" {
-		bytes, err := os.ReadFile(result.Path)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read schema %s: %w", result.Path, err)
+	if _, ok := snap.docs[schemaUrl]; ok || result.Scheme == "file" {
+		s.mutex.RLock()
+		cached, ok := s.parsedSchemas[schemaUrl]
+		s.mutex.RUnlock()
+		if ok && cached.version == key.version {
+			return cached.schema, key, cached.err
+		}
+
+		var parsed *schema.Schema
+		if schemaDoc, ok := snap.docs[schemaUrl]; ok {
+			parsed, err = schema.Parse([]byte(schemaDoc.Content))
+		} else if bytes, readErr := os.ReadFile(result.Path); readErr != nil {
+			err = fmt.Errorf("failed to read schema %s: %w", result.Path, readErr)
+		} else {
+			parsed, err = schema.Parse(bytes)
 		}
-		return schema.Parse(bytes)
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		s.parsedSchemas[schemaUrl] = parsedSchema{key.version, parsed, err}
+		return parsed, key, err
 	}
 	if result.Scheme == "https" || result.Scheme == "http" {
 		s.mutex.Lock()
@@ -348,26 +505,33 @@ func (s *Server) loadSchema(docUrl lsp.DocumentURI, requested string) (*schema.S
 		s.mutex.Unlock()
 		if ok {
 			if cached.schema != nil {
-				return cached.schema, nil
+				return cached.schema, key, nil
 			}
 			if !shouldLoad {
-				return nil, cached.err
+				return nil, key, cached.err
 			}
 		}
-		schema, err := s.loadHTTPSchema(result)
+		schema, err := s.loadHTTPSchema(ctx, result)
+		if ctx.Err() != nil {
+			return nil, key, ctx.Err()
+		}
 		s.mutex.Lock()
 		defer s.mutex.Unlock()
 		s.httpSchemas[schemaUrl] = httpSchema{schema, err}
 		if err != nil {
-			return nil, err
+			return nil, key, err
 		}
-		return schema, nil
+		return schema, key, nil
 	}
-	return nil, fmt.Errorf("unsupported schema location: %v", result)
+	return nil, key, fmt.Errorf("unsupported schema location: %v", result)
 }
 
-func (s *Server) loadHTTPSchema(uri *url.URL) (*schema.Schema, error) {
-	resp, err := http.Get(uri.String())
+func (s *Server) loadHTTPSchema(ctx context.Context, uri *url.URL) (*schema.Schema, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -383,62 +547,38 @@ func (s *Server) loadHTTPSchema(uri *url.URL) (*schema.Schema, error) {
 	return schema.Parse(bytes)
 }
 
-func (s *Server) updateDiagnostics(doc *TextDocument) {
-	defer logPanic()
+// validate returns the diagnostics for doc.
+func (s *Server) validate(ctx context.Context, doc *TextDocument) []lsp.Diagnostic {
+	return diagnosticsFor(doc, s.analyze(ctx, s.snapshot(), doc).result)
+}
 
-	errs := schema.Validate([]byte(doc.Content), func(name string) (*schema.Schema, error) {
-		return s.loadSchema(doc.URI, name)
-	}).Errors()
+// diagnosticsFor converts the errors in result to diagnostics for doc.
+func diagnosticsFor(doc *TextDocument, result *schema.ValidationResult) []lsp.Diagnostic {
+	errs := result.Errors()
 
-	if len(errs) > 0 {
-		diagnostics := make([]*lsp.Diagnostic, len(errs))
-		for i, err := range errs {
-			line := strings.Split(doc.Content, "\n")[err.Lno()-1]
-			start, end := err.RuneRange(line)
+	diagnostics := make([]lsp.Diagnostic, len(errs))
+	for i, err := range errs {
+		line := doc.line(err.Lno() - 1)
+		start, end := err.RuneRange(line)
 
-			diagnostics[i] = &lsp.Diagnostic{
-				Range: lsp.Range{
-					Start: lsp.Position{
-						Line:      uint32(err.Lno() - 1),
-						Character: utf16Len(line[:start]),
-					},
-					End: lsp.Position{
-						Line:      uint32(err.Lno() - 1),
-						Character: utf16Len(line[:end]),
-					},
+		diagnostics[i] = lsp.Diagnostic{
+			Range: lsp.Range{
+				Start: lsp.Position{
+					Line:      uint32(err.Lno() - 1),
+					Character: doc.Encoding.Len(line[:start]),
 				},
-				Severity: lsp.DiagnosticSeverityError,
-				Message:  err.Msg(),
-			}
+				End: lsp.Position{
+					Line:      uint32(err.Lno() - 1),
+					Character: doc.Encoding.Len(line[:end]),
+				},
+			},
+			Severity: lsp.DiagnosticSeverityError,
+			Message:  err.Msg(),
 		}
-
-		s.PublishDiagnostics(&lsp.PublishDiagnosticsParams{
-			URI:         doc.URI,
-			Version:     doc.Version,
-			Diagnostics: diagnostics,
-		})
-	} else {
-		s.ClearDiagnostics(doc)
-	}
-}
-
-func utf16Len(s string) uint32 {
-	ret := uint32(0)
-	for _, r := range s {
-		ret += uint32(utf16.RuneLen(r))
 	}
-
-	return ret
+	return diagnostics
 }
 
 func (s *Server) PublishDiagnostics(params *lsp.PublishDiagnosticsParams) {
 	s.c.Notify("textDocument/publishDiagnostics", params)
 }
-
-func (s *Server) ClearDiagnostics(doc *TextDocument) {
-	s.PublishDiagnostics(&lsp.PublishDiagnosticsParams{
-		URI:         doc.URI,
-		Version:     doc.Version,
-		Diagnostics: []*lsp.Diagnostic{},
-	})
-}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"iter"
 	"os"
+	"os/exec"
 	"reflect"
 	"strings"
 	"sync/atomic"
@@ -18,13 +19,19 @@ import (
 )
 
 func bootServer() (*io.PipeWriter, *io.PipeReader) {
+	return bootServerOn(lsp.NewConnection())
+}
+
+func bootServerOn(c *lsp.Connection) (*io.PipeWriter, *io.PipeReader) {
+	return bootServerWith(NewServer(c))
+}
+
+func bootServerWith(s *Server) (*io.PipeWriter, *io.PipeReader) {
 	readIn, writeIn := io.Pipe()
 	readOut, writeOut := io.Pipe()
 
-	c := lsp.NewConnection()
-
 	go func() {
-		err := NewServer(c).Serve(context.Background(),
+		err := s.Serve(context.Background(),
 			readIn, writeOut)
 		if err != nil {
 			panic(err)
@@ -41,7 +48,11 @@ type testServer struct {
 }
 
 func newTestServer(t *testing.T) *testServer {
-	in, out := bootServer()
+	return newTestServerOn(t, lsp.NewConnection())
+}
+
+func newTestServerOn(t *testing.T, c *lsp.Connection) *testServer {
+	in, out := bootServerOn(c)
 	readFrame, stop := iter.Pull2(lsp.ReadFrames(out))
 	ch := make(chan *lsp.Frame)
 	t.Cleanup(stop)
@@ -60,8 +71,12 @@ func newTestServer(t *testing.T) *testServer {
 }
 
 func newTestServerFor(t *testing.T, content string) (lsp.DocumentURI, *testServer) {
+	return newTestServerWith(t, lsp.InitializeParams{}, content)
+}
+
+func newTestServerWith(t *testing.T, params lsp.InitializeParams, content string) (lsp.DocumentURI, *testServer) {
 	server := newTestServer(t)
-	testRequest[lsp.InitializeResult](server, "initialize", lsp.InitializeParams{})
+	testRequest[lsp.InitializeResult](server, "initialize", params)
 	wd, err := os.Getwd()
 	if err != nil {
 		t.Fatal(err)
@@ -96,7 +111,7 @@ func contentPos(input string) (string, lsp.Position) {
 	lines := strings.Split(before, "\n")
 	return before + after, lsp.Position{
 		Line:      uint32(len(lines)) - 1,
-		Character: utf16Len(lines[len(lines)-1]),
+		Character: lsp.PositionEncodingKindUTF16.Len(lines[len(lines)-1]),
 	}
 
 }
@@ -201,12 +216,14 @@ func TestInitialize(t *testing.T) {
 func TestHover(t *testing.T) {
 	uri, server := newTestServerFor(t, "schema = ./docs.conl\ntest\n")
 	hover := testRequest[lsp.Hover](server, "textDocument/hover", lsp.HoverParams{
-		TextDocument: lsp.TextDocumentIdentifier{
-			URI: uri,
-		},
-		Position: lsp.Position{
-			Line:      1,
-			Character: 1,
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{
+				URI: uri,
+			},
+			Position: lsp.Position{
+				Line:      1,
+				Character: 1,
+			},
 		},
 	})
 
@@ -222,12 +239,14 @@ func TestHover(t *testing.T) {
 	}
 
 	hover = testRequest[lsp.Hover](server, "textDocument/hover", lsp.HoverParams{
-		TextDocument: lsp.TextDocumentIdentifier{
-			URI: uri,
-		},
-		Position: lsp.Position{
-			Line:      0,
-			Character: 1,
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{
+				URI: uri,
+			},
+			Position: lsp.Position{
+				Line:      0,
+				Character: 1,
+			},
 		},
 	})
 	expected = nil
@@ -237,6 +256,40 @@ func TestHover(t *testing.T) {
 	}
 }
 
+func TestHoverPlainText(t *testing.T) {
+	uri, server := newTestServerWith(t, lsp.InitializeParams{
+		Capabilities: lsp.ClientCapabilities{
+			TextDocument: &lsp.TextDocumentClientCapabilities{
+				Hover: &lsp.HoverClientCapabilities{
+					ContentFormat: []lsp.MarkupKind{lsp.MarkupKindPlainText},
+				},
+			},
+		},
+	}, "schema = ./docs.conl\ntest\n")
+	hover := testRequest[lsp.Hover](server, "textDocument/hover", lsp.HoverParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{
+				URI: uri,
+			},
+			Position: lsp.Position{
+				Line:      1,
+				Character: 1,
+			},
+		},
+	})
+
+	expected := &lsp.Hover{
+		Contents: &lsp.MarkupContent{
+			Kind:  lsp.MarkupKindPlainText,
+			Value: "The test key",
+		},
+	}
+
+	if !reflect.DeepEqual(hover, expected) {
+		t.Fatalf("got %#v, expected %#v", hover, expected)
+	}
+}
+
 func expectCompletions(t *testing.T, list *lsp.CompletionList, expected ...string) {
 	t.Helper()
 	var actual []string
@@ -253,10 +306,12 @@ func TestKeyCompletion(t *testing.T) {
 	uri, server := newTestServerFor(t, content)
 
 	completions := testRequest[lsp.CompletionList](server, "textDocument/completion", lsp.CompletionParams{
-		TextDocument: lsp.TextDocumentIdentifier{
-			URI: uri,
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{
+				URI: uri,
+			},
+			Position: position,
 		},
-		Position: position,
 	})
 	expectCompletions(t, completions, "completion", "value")
 }
@@ -266,10 +321,12 @@ func TestValueCompletion(t *testing.T) {
 	uri, server := newTestServerFor(t, content)
 
 	completions := testRequest[lsp.CompletionList](server, "textDocument/completion", lsp.CompletionParams{
-		TextDocument: lsp.TextDocumentIdentifier{
-			URI: uri,
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{
+				URI: uri,
+			},
+			Position: position,
 		},
-		Position: position,
 	})
 	expectCompletions(t, completions, "alpha", "ant", "beta")
 }
@@ -279,10 +336,387 @@ func TestCommentCompletion(t *testing.T) {
 	uri, server := newTestServerFor(t, content)
 
 	completions := testRequest[lsp.CompletionList](server, "textDocument/completion", lsp.CompletionParams{
-		TextDocument: lsp.TextDocumentIdentifier{
-			URI: uri,
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{
+				URI: uri,
+			},
+			Position: position,
 		},
-		Position: position,
 	})
 	expectCompletions(t, completions)
 }
+
+func TestPositionEncoding(t *testing.T) {
+	server := newTestServer(t)
+	result := testRequest[lsp.InitializeResult](server, "initialize", lsp.InitializeParams{
+		Capabilities: lsp.ClientCapabilities{
+			General: &lsp.GeneralClientCapabilities{
+				PositionEncodings: []lsp.PositionEncodingKind{"utf-7", lsp.PositionEncodingKindUTF8, lsp.PositionEncodingKindUTF16},
+			},
+		},
+	})
+	if result.Capabilities.PositionEncoding != lsp.PositionEncodingKindUTF8 {
+		t.Fatalf("got %#v, expected utf-8", result.Capabilities.PositionEncoding)
+	}
+}
+
+// initializeWithParent starts a server that is sent the pid of a process
+// as the client's processId, and returns that process and the server's output.
+func initializeWithParent(t *testing.T, watchClient bool) (*exec.Cmd, io.Reader) {
+	t.Helper()
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not available")
+	}
+	parent := exec.Command("sleep", "60")
+	if err := parent.Start(); err != nil {
+		t.Fatal(err)
+	}
+	interval := parentPollInterval
+	parentPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { parentPollInterval = interval })
+
+	s := NewServer(lsp.NewConnection())
+	s.watchClient = watchClient
+	in, out := bootServerWith(s)
+	t.Cleanup(func() { in.Close() })
+	pid := int32(parent.Process.Pid)
+	msg, err := json.Marshal(lsp.Frame{
+		JsonRPC: "2.0",
+		Id:      json.RawMessage(`0`),
+		Method:  "initialize",
+		Params:  json.RawMessage(fmt.Sprintf(`{"processId":%d}`, pid)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	in.Write([]byte(fmt.Sprintf("Content-Length: %d\r\n\r\n", len(msg))))
+	in.Write(msg)
+	return parent, out
+}
+
+// exited returns a channel that is closed once the server closes out.
+func exited(out io.Reader) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		io.ReadAll(out)
+		close(done)
+	}()
+	return done
+}
+
+func TestParentProcessExit(t *testing.T) {
+	parent, out := initializeWithParent(t, true)
+	parent.Process.Kill()
+	parent.Wait()
+
+	select {
+	case <-exited(out):
+	case <-time.After(time.Second):
+		t.Fatal("server did not exit when the parent process did")
+	}
+}
+
+func TestParentProcessIgnoredOverSockets(t *testing.T) {
+	parent, out := initializeWithParent(t, false)
+	parent.Process.Kill()
+	parent.Wait()
+
+	select {
+	case <-exited(out):
+		t.Fatal("server exited when the processId was not being watched")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestPullDiagnostics(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := lsp.DocumentURI("file://" + wd + "/testdata")
+	uri, server := newTestServerWith(t, lsp.InitializeParams{
+		Capabilities: lsp.ClientCapabilities{
+			TextDocument: &lsp.TextDocumentClientCapabilities{
+				Diagnostic: &lsp.DiagnosticClientCapabilities{},
+			},
+		},
+		WorkspaceFoldersInitializeParams: lsp.WorkspaceFoldersInitializeParams{
+			WorkspaceFolders: []lsp.WorkspaceFolder{{URI: root, Name: "testdata"}},
+		},
+	}, "a = b\n")
+
+	report := testRequest[lsp.RelatedFullDocumentDiagnosticReport](server, "textDocument/diagnostic", lsp.DocumentDiagnosticParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+	})
+	if report.Kind != "full" || report.ResultID == "" {
+		t.Fatalf("expected a full report, got %#v", report)
+	}
+	unchanged := testRequest[lsp.RelatedUnchangedDocumentDiagnosticReport](server, "textDocument/diagnostic", lsp.DocumentDiagnosticParams{
+		TextDocument:     lsp.TextDocumentIdentifier{URI: uri},
+		PreviousResultID: report.ResultID,
+	})
+	if unchanged.Kind != "unchanged" || unchanged.ResultID != report.ResultID {
+		t.Fatalf("expected an unchanged report, got %#v", unchanged)
+	}
+
+	workspace := testRequest[lsp.WorkspaceDiagnosticReport](server, "workspace/diagnostic", lsp.WorkspaceDiagnosticParams{
+		PreviousResultIDs: []lsp.PreviousResultId{{URI: root + "/docs.conl", Value: report.ResultID}},
+	})
+	kinds := map[lsp.DocumentURI]string{}
+	for _, raw := range workspace.Items {
+		item := lsp.WorkspaceFullDocumentDiagnosticReport{}
+		if err := json.Unmarshal(raw, &item); err != nil {
+			t.Fatal(err)
+		}
+		kinds[item.URI] = item.Kind
+		if item.Version != nil {
+			t.Fatalf("unexpected version for unopened file %s", item.URI)
+		}
+	}
+	expected := map[lsp.DocumentURI]string{
+		root + "/completions.conl": "full",
+		root + "/docs.conl":        "unchanged",
+	}
+	if !reflect.DeepEqual(kinds, expected) {
+		t.Fatalf("got %#v, expected %#v", kinds, expected)
+	}
+}
+
+func TestWorkspaceDiagnosticWaitsForChanges(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := lsp.DocumentURI("file://" + wd + "/testdata")
+	uri, server := newTestServerWith(t, lsp.InitializeParams{
+		Capabilities: lsp.ClientCapabilities{
+			TextDocument: &lsp.TextDocumentClientCapabilities{
+				Diagnostic: &lsp.DiagnosticClientCapabilities{},
+			},
+		},
+		WorkspaceFoldersInitializeParams: lsp.WorkspaceFoldersInitializeParams{
+			WorkspaceFolders: []lsp.WorkspaceFolder{{URI: root, Name: "testdata"}},
+		},
+	}, "a = b\n")
+
+	first := testRequest[lsp.WorkspaceDiagnosticReport](server, "workspace/diagnostic", lsp.WorkspaceDiagnosticParams{})
+	previous := []lsp.PreviousResultId{}
+	for _, raw := range first.Items {
+		item := lsp.WorkspaceFullDocumentDiagnosticReport{}
+		if err := json.Unmarshal(raw, &item); err != nil {
+			t.Fatal(err)
+		}
+		previous = append(previous, lsp.PreviousResultId{URI: item.URI, Value: item.ResultID})
+	}
+
+	id := nextId()
+	response := make(chan *lsp.Frame, 1)
+	go func() {
+		defer close(response)
+		for {
+			frame, err, ok := server.readFrame()
+			if !ok || err != nil {
+				return
+			}
+			if bytes.Equal(frame.Id, id) {
+				response <- frame
+				return
+			}
+		}
+	}()
+
+	raw, err := json.Marshal(lsp.WorkspaceDiagnosticParams{PreviousResultIDs: previous})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.writer <- &lsp.Frame{JsonRPC: "2.0", Id: id, Method: "workspace/diagnostic", Params: raw}
+	testNotify(server, "textDocument/didChange", lsp.DidChangeTextDocumentParams{
+		TextDocument:   lsp.VersionedTextDocumentIdentifier{TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: uri}, Version: 2},
+		ContentChanges: []lsp.TextDocumentContentChangeEvent{{Text: "a = c\n"}},
+	})
+	select {
+	case frame := <-response:
+		t.Fatalf("expected the request to wait for a change to the workspace, got %#v", frame)
+	case <-time.After(2 * diagnosticsDelay):
+	}
+
+	cancel, err := json.Marshal(lsp.CancelParams{ID: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.writer <- &lsp.Frame{JsonRPC: "2.0", Method: "$/cancelRequest", Params: cancel}
+	select {
+	case frame := <-response:
+		if frame == nil || frame.Error == nil || frame.Error.Code != lsp.ERequestCancelled {
+			t.Fatalf("got %#v, expected RequestCancelled", frame)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+}
+
+func TestWorkspaceDiagnosticEndsOnDisconnect(t *testing.T) {
+	in, out := bootServer()
+	done := exited(out)
+
+	// an empty workspace is unchanged, so the request is held straight away
+	root := lsp.DocumentURI("file://" + t.TempDir())
+	for _, frame := range []struct {
+		method string
+		params any
+	}{
+		{"initialize", lsp.InitializeParams{RootURI: &root}},
+		{"workspace/diagnostic", lsp.WorkspaceDiagnosticParams{}},
+	} {
+		raw, err := json.Marshal(frame.params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, err := json.Marshal(lsp.Frame{JsonRPC: "2.0", Id: nextId(), Method: frame.method, Params: raw})
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(in, "Content-Length: %d\r\n\r\n%s", len(msg), msg)
+	}
+	time.Sleep(2 * diagnosticsDelay)
+	in.Close()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("server did not exit after the client disconnected")
+	}
+}
+
+// TestConcurrentEdits should be run with -race.
+func TestConcurrentEdits(t *testing.T) {
+	uri, server := newTestServerFor(t, "schema = ./docs.conl\ntest\n")
+	schemaURI := uri[:strings.LastIndex(string(uri), "/")] + "/docs.conl"
+	testNotify(server, "textDocument/didOpen", lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: schemaURI, LanguageID: "conl", Version: 1, Text: "root\n"},
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for version := range int32(200) {
+			target := uri
+			if version%2 == 1 {
+				target = schemaURI
+			}
+			testNotify(server, "textDocument/didChange", lsp.DidChangeTextDocumentParams{
+				TextDocument: lsp.VersionedTextDocumentIdentifier{TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: target}, Version: version + 2},
+				ContentChanges: []lsp.TextDocumentContentChangeEvent{{
+					Range: &lsp.Range{Start: lsp.Position{Line: 1, Character: 0}, End: lsp.Position{Line: 1, Character: 0}},
+					Text:  "x",
+				}},
+			})
+		}
+	}()
+
+	for range 50 {
+		testRequest[lsp.Hover](server, "textDocument/hover", lsp.HoverParams{
+			TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+				TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+				Position:     lsp.Position{Line: 1, Character: 1},
+			},
+		})
+		testRequest[lsp.CompletionList](server, "textDocument/completion", lsp.CompletionParams{
+			TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+				TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+				Position:     lsp.Position{Line: 1, Character: 1},
+			},
+		})
+	}
+	<-done
+}
+
+func TestAnalysisCache(t *testing.T) {
+	s := NewServer(lsp.NewConnection())
+	ctx := context.Background()
+	open := func(uri lsp.DocumentURI, text string) {
+		s.textDocumentDidOpen(ctx, &lsp.DidOpenTextDocumentParams{
+			TextDocument: lsp.TextDocumentItem{URI: uri, LanguageID: "conl", Version: 1, Text: text},
+		})
+	}
+	change := func(uri lsp.DocumentURI, version int32, text string) {
+		s.textDocumentDidChange(ctx, &lsp.DidChangeTextDocumentParams{
+			TextDocument:   lsp.VersionedTextDocumentIdentifier{TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: uri}, Version: version},
+			ContentChanges: []lsp.TextDocumentContentChangeEvent{{Text: text}},
+		})
+	}
+	analyze := func(uri lsp.DocumentURI) *analysis {
+		snap := s.snapshot()
+		return s.analyze(ctx, snap, snap.docs[uri])
+	}
+
+	open("file:///a.conl", "schema = ./schema.conl\n")
+	open("file:///schema.conl", "root\n")
+	first := analyze("file:///a.conl")
+	if analyze("file:///a.conl") != first {
+		t.Fatal("expected the analysis to be reused")
+	}
+
+	change("file:///a.conl", 2, "schema = ./schema.conl\na = b\n")
+	second := analyze("file:///a.conl")
+	if second == first || second.version != 2 {
+		t.Fatal("expected an edit to invalidate the analysis")
+	}
+	if expected := (schemaKey{uri: "file:///schema.conl", version: "open@1"}); second.schema != expected {
+		t.Fatalf("got %#v, expected %#v", second.schema, expected)
+	}
+	if analyze("file:///a.conl") != second {
+		t.Fatal("expected the analysis to be reused")
+	}
+
+	change("file:///schema.conl", 2, "root\n  a = b\n")
+	third := analyze("file:///a.conl")
+	if third == second || third.schema.version != "open@2" {
+		t.Fatal("expected an edit to the schema to invalidate the analysis")
+	}
+}
+
+func TestWorkspaceReportCache(t *testing.T) {
+	s := NewServer(lsp.NewConnection())
+	ctx := context.Background()
+	dir := t.TempDir()
+	root := lsp.DocumentURI("file://" + dir)
+	s.client.RootURI = &root
+	write := func(name, text string) {
+		if err := os.WriteFile(dir+"/"+name, []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	report := func() *fileReport {
+		if _, _, err := s.workspaceReport(ctx, nil); err != nil {
+			t.Fatal(err)
+		}
+		return s.fileReports[root+"/a.conl"]
+	}
+
+	write("a.conl", "schema = ./schema.conl\n")
+	write("schema.conl", "root\n")
+	first := report()
+	if first == nil || first.schema.uri != root+"/schema.conl" {
+		t.Fatalf("got %#v, expected a report using the schema", first)
+	}
+	if report() != first {
+		t.Fatal("expected the report to be reused")
+	}
+
+	write("a.conl", "schema = ./schema.conl\na = b\n")
+	second := report()
+	if second == first {
+		t.Fatal("expected an edit to invalidate the report")
+	}
+	if report() != second {
+		t.Fatal("expected the report to be reused")
+	}
+
+	s.textDocumentDidOpen(ctx, &lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: root + "/schema.conl", LanguageID: "conl", Version: 1, Text: "root\n  a = b\n"},
+	})
+	if third := report(); third == second || third.schema.version != "open@1" {
+		t.Fatal("expected an edit to the schema to invalidate the report")
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"maps"
+
+	"github.com/ConradIrwin/conl-lsp/lsp"
+)
+
+// A snapshot is an immutable view of the open documents, and of the schema
+// that each document uses. Handlers take the current snapshot and work
+// against it without locking; changes replace it with an updated copy.
+type snapshot struct {
+	docs         map[lsp.DocumentURI]*TextDocument
+	schemasInUse map[lsp.DocumentURI]lsp.DocumentURI
+}
+
+func (s *Server) snapshot() *snapshot {
+	return s.current.Load()
+}
+
+// update replaces the current snapshot with a copy modified by fn.
+// s.mutex must be held.
+func (s *Server) update(fn func(next *snapshot)) *snapshot {
+	prev := s.current.Load()
+	next := &snapshot{
+		docs:         maps.Clone(prev.docs),
+		schemasInUse: maps.Clone(prev.schemasInUse),
+	}
+	fn(next)
+	s.current.Store(next)
+	return next
+}
+
+// dependents returns the open documents that use uri as their schema.
+func (snap *snapshot) dependents(uri lsp.DocumentURI) []*TextDocument {
+	docs := []*TextDocument{}
+	for doc, schema := range snap.schemasInUse {
+		if schema == uri {
+			if doc, ok := snap.docs[doc]; ok {
+				docs = append(docs, doc)
+			}
+		}
+	}
+	return docs
+}
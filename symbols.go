@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ConradIrwin/conl-go"
+	"github.com/ConradIrwin/conl-go/schema"
+	"github.com/ConradIrwin/conl-lsp/lsp"
+)
+
+func (s *Server) textDocumentDocumentSymbol(ctx context.Context, params *lsp.DocumentSymbolParams) (any, error) {
+	snap := s.snapshot()
+	doc, ok := snap.docs[params.TextDocument.URI]
+	if !ok {
+		return nil, fmt.Errorf("document %v not found", params.TextDocument.URI)
+	}
+	symbols := documentSymbols(doc, s.analyze(ctx, snap, doc).result)
+	if !s.hierarchicalSymbols() {
+		return flattenSymbols(doc.URI, "", symbols), nil
+	}
+	return symbols, nil
+}
+
+// hierarchicalSymbols reports whether the client accepts DocumentSymbol
+// results. Other clients only understand a flat list of SymbolInformation.
+func (s *Server) hierarchicalSymbols() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	caps := s.client.Capabilities.TextDocument
+	return caps != nil && caps.DocumentSymbol != nil && caps.DocumentSymbol.HierarchicalDocumentSymbolSupport
+}
+
+// flattenSymbols returns symbols and their children in document order. Each
+// is contained by the dotted path of its parent (e.g. database.replicas).
+func flattenSymbols(uri lsp.DocumentURI, container string, symbols []lsp.DocumentSymbol) []lsp.SymbolInformation {
+	flat := []lsp.SymbolInformation{}
+	for _, symbol := range symbols {
+		flat = append(flat, lsp.SymbolInformation{
+			BaseSymbolInformation: lsp.BaseSymbolInformation{
+				Name:          symbol.Name,
+				Kind:          symbol.Kind,
+				ContainerName: container,
+			},
+			Location: lsp.Location{URI: uri, Range: symbol.Range},
+		})
+		path := symbol.Name
+		if container != "" {
+			path = container + "." + path
+		}
+		flat = append(flat, flattenSymbols(uri, path, symbol.Children)...)
+	}
+	return flat
+}
+
+// documentSymbols returns the outline of doc: a symbol for each map key and
+// list item, nested under the key or item that contains it. Scalar values
+// are shown as the detail of their symbol, and sections without a value
+// show the first line of their documentation from the schema instead.
+// Documents that do not parse are outlined up to the first error. If result
+// is nil, no documentation is shown.
+func documentSymbols(doc *TextDocument, result *schema.ValidationResult) []lsp.DocumentSymbol {
+	lines := parseLines(doc, doc.lineCount())
+	root := &lsp.DocumentSymbol{}
+	parents := []*lsp.DocumentSymbol{root}
+	var current *lsp.DocumentSymbol
+
+	// finish adds the current symbol to its parent once all of its
+	// children are known.
+	finish := func() {
+		if current == nil {
+			return
+		}
+		if current.Detail == "" && len(current.Children) > 0 && result != nil {
+			docs := result.DocsForKey(int(current.Range.Start.Line) + 1)
+			current.Detail, _, _ = strings.Cut(strings.TrimSpace(docs), "\n")
+		}
+		parent := parents[len(parents)-1]
+		parent.Children = append(parent.Children, *current)
+		current = nil
+	}
+
+	for token := range conl.Tokens([]byte(doc.Content)) {
+		if token.Error != nil {
+			break
+		}
+		switch token.Kind {
+		case conl.Indent:
+			if current != nil {
+				parents = append(parents, current)
+				current = nil
+			}
+		case conl.Outdent:
+			if len(parents) > 1 {
+				finish()
+				current = parents[len(parents)-1]
+				parents = parents[:len(parents)-1]
+			}
+		case conl.MapKey, conl.ListItem:
+			finish()
+			parent := parents[len(parents)-1]
+			current = newSymbol(doc, lines, token.Lno-1)
+			current.Name = token.Content
+			if token.Kind == conl.ListItem {
+				current.Name = strconv.Itoa(len(parent.Children))
+				current.Kind = lsp.SymbolKindString
+				// the selection is the = that starts the item
+				indent := doc.Encoding.Len(lines[token.Lno-1].indent)
+				current.SelectionRange.Start.Character = indent
+				current.SelectionRange.End.Character = indent + 1
+			}
+			if parent != root {
+				parent.Kind = lsp.SymbolKindObject
+				if token.Kind == conl.ListItem {
+					parent.Kind = lsp.SymbolKindArray
+				}
+			}
+		case conl.Scalar:
+			if current != nil {
+				current.Detail = token.Content
+			}
+		case conl.MultilineScalar:
+			if current != nil {
+				current.Detail, _, _ = strings.Cut(token.Content, "\n")
+			}
+		}
+	}
+	for len(parents) > 1 {
+		finish()
+		current = parents[len(parents)-1]
+		parents = parents[:len(parents)-1]
+	}
+	finish()
+
+	if root.Children == nil {
+		return []lsp.DocumentSymbol{}
+	}
+	return root.Children
+}
+
+// newSymbol returns a symbol for the key or list item on line lno. Its range
+// covers the line and everything nested under it, and its selection range
+// covers the key.
+func newSymbol(doc *TextDocument, lines []*formatLine, lno int) *lsp.DocumentSymbol {
+	line := doc.line(lno)
+	keyStart, keyEnd, _, _, _ := schema.SplitLine(line)
+	if keyEnd < keyStart {
+		keyEnd = keyStart
+	}
+
+	last := blockEnd(lines, lno)
+	return &lsp.DocumentSymbol{
+		Kind: lsp.SymbolKindProperty,
+		Range: lsp.Range{
+			Start: lsp.Position{Line: uint32(lno)},
+			End:   lsp.Position{Line: uint32(last), Character: doc.Encoding.Len(doc.line(last))},
+		},
+		SelectionRange: lsp.Range{
+			Start: lsp.Position{Line: uint32(lno), Character: doc.Encoding.Len(line[:keyStart])},
+			End:   lsp.Position{Line: uint32(lno), Character: doc.Encoding.Len(line[:keyEnd])},
+		},
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/ConradIrwin/conl-go/schema"
+	"github.com/ConradIrwin/conl-lsp/lsp"
+)
+
+func lineRange(start, startChar, end, endChar uint32) lsp.Range {
+	return lsp.Range{
+		Start: lsp.Position{Line: start, Character: startChar},
+		End:   lsp.Position{Line: end, Character: endChar},
+	}
+}
+
+func TestDocumentSymbols(t *testing.T) {
+	content := "name = conl\nservers\n  local\n    port = 8080\n  ; comment\ntags\n  = alpha\n  = beta\nquery = \"\"\"sql\n  select *\n\n"
+	doc := NewTextDocument("file:///test.conl", 1, content, "conl", lsp.PositionEncodingKindUTF16)
+	result := schema.Validate([]byte(content), func(string) (*schema.Schema, error) { return schema.Any(), nil })
+
+	expected := []lsp.DocumentSymbol{
+		{Name: "name", Detail: "conl", Kind: lsp.SymbolKindProperty, Range: lineRange(0, 0, 0, 11), SelectionRange: lineRange(0, 0, 0, 4)},
+		{Name: "servers", Kind: lsp.SymbolKindObject, Range: lineRange(1, 0, 3, 15), SelectionRange: lineRange(1, 0, 1, 7), Children: []lsp.DocumentSymbol{
+			{Name: "local", Kind: lsp.SymbolKindObject, Range: lineRange(2, 0, 3, 15), SelectionRange: lineRange(2, 2, 2, 7), Children: []lsp.DocumentSymbol{
+				{Name: "port", Detail: "8080", Kind: lsp.SymbolKindProperty, Range: lineRange(3, 0, 3, 15), SelectionRange: lineRange(3, 4, 3, 8)},
+			}},
+		}},
+		{Name: "tags", Kind: lsp.SymbolKindArray, Range: lineRange(5, 0, 7, 8), SelectionRange: lineRange(5, 0, 5, 4), Children: []lsp.DocumentSymbol{
+			{Name: "0", Detail: "alpha", Kind: lsp.SymbolKindString, Range: lineRange(6, 0, 6, 9), SelectionRange: lineRange(6, 2, 6, 3)},
+			{Name: "1", Detail: "beta", Kind: lsp.SymbolKindString, Range: lineRange(7, 0, 7, 8), SelectionRange: lineRange(7, 2, 7, 3)},
+		}},
+		{Name: "query", Detail: "select *", Kind: lsp.SymbolKindProperty, Range: lineRange(8, 0, 9, 10), SelectionRange: lineRange(8, 0, 8, 5)},
+	}
+	if symbols := documentSymbols(doc, result); !reflect.DeepEqual(symbols, expected) {
+		t.Fatalf("got %#v, expected %#v", symbols, expected)
+	}
+}
+
+func TestDocumentSymbolsEmpty(t *testing.T) {
+	doc := NewTextDocument("file:///test.conl", 1, "; just a comment\n", "conl", lsp.PositionEncodingKindUTF16)
+	result := schema.Validate([]byte(doc.Content), func(string) (*schema.Schema, error) { return schema.Any(), nil })
+	if symbols := documentSymbols(doc, result); symbols == nil || len(symbols) != 0 {
+		t.Fatalf("got %#v, expected no symbols", symbols)
+	}
+}
+
+func TestDocumentSymbolsFlat(t *testing.T) {
+	s := NewServer(lsp.NewConnection())
+	ctx := context.Background()
+	uri := lsp.DocumentURI("file:///test.conl")
+	s.textDocumentDidOpen(ctx, &lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: uri, LanguageID: "conl", Version: 1, Text: "servers\n  local\n    port = 8080\n"},
+	})
+	params := &lsp.DocumentSymbolParams{TextDocument: lsp.TextDocumentIdentifier{URI: uri}}
+
+	result, err := s.textDocumentDocumentSymbol(ctx, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	symbol := func(name string, kind lsp.SymbolKind, container string, r lsp.Range) lsp.SymbolInformation {
+		return lsp.SymbolInformation{
+			BaseSymbolInformation: lsp.BaseSymbolInformation{Name: name, Kind: kind, ContainerName: container},
+			Location:              lsp.Location{URI: uri, Range: r},
+		}
+	}
+	expected := []lsp.SymbolInformation{
+		symbol("servers", lsp.SymbolKindObject, "", lineRange(0, 0, 2, 15)),
+		symbol("local", lsp.SymbolKindObject, "servers", lineRange(1, 0, 2, 15)),
+		symbol("port", lsp.SymbolKindProperty, "servers.local", lineRange(2, 0, 2, 15)),
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("got %#v, expected %#v", result, expected)
+	}
+
+	s.client.Capabilities.TextDocument = &lsp.TextDocumentClientCapabilities{
+		DocumentSymbol: &lsp.DocumentSymbolClientCapabilities{HierarchicalDocumentSymbolSupport: true},
+	}
+	result, err = s.textDocumentDocumentSymbol(ctx, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if symbols, ok := result.([]lsp.DocumentSymbol); !ok || len(symbols) != 1 || len(symbols[0].Children) != 1 {
+		t.Fatalf("got %#v, expected hierarchical symbols", result)
+	}
+}
@@ -3,8 +3,9 @@ package main
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
-	"unicode/utf16"
+	"unicode/utf8"
 
 	"github.com/ConradIrwin/conl-lsp/lsp"
 )
@@ -14,6 +15,12 @@ type TextDocument struct {
 	Version  int32
 	Content  string
 	Language string
+	// Encoding is the negotiated encoding of positions sent by the client.
+	Encoding lsp.PositionEncodingKind
+
+	// lineStarts is the byte offset of the start of each line in Content.
+	// It is shared between clones, and so must be replaced rather than modified.
+	lineStarts []int
 }
 
 var lineEndRe = regexp.MustCompile(`\r\n?`)
@@ -25,13 +32,15 @@ func normalizeNewlines(s string) string {
 	return s
 }
 
-func NewTextDocument(uri lsp.DocumentURI, version int32, content string, language string) *TextDocument {
-	return &TextDocument{
+func NewTextDocument(uri lsp.DocumentURI, version int32, content string, language string, encoding lsp.PositionEncodingKind) *TextDocument {
+	t := &TextDocument{
 		URI:      uri,
 		Version:  version,
-		Content:  normalizeNewlines(content),
 		Language: language,
+		Encoding: encoding,
 	}
+	t.setContent(normalizeNewlines(content))
+	return t
 }
 
 func (t *TextDocument) Clone() *TextDocument {
@@ -39,85 +48,112 @@ func (t *TextDocument) Clone() *TextDocument {
 	return &clone
 }
 
-func (t *TextDocument) applyChange(change lsp.TextDocumentContentChangeEvent) {
-	content := normalizeNewlines(change.Text)
-	if change.Range == nil {
-		t.Content = content
-		return
-	}
-	start := t.resolve(change.Range.Start)
-	end := t.resolve(change.Range.End)
-	t.Content = t.Content[:start] + content + t.Content[end:]
+func (t *TextDocument) setContent(content string) {
+	t.Content = content
+	t.lineStarts = append([]int{0}, newlineOffsets(content, 0)...)
 }
 
-func (t *TextDocument) lines() []string {
-	return strings.Split(t.Content, "\n")
+// newlineOffsets returns the offsets of the start of each line after
+// a newline in s, assuming that s starts at offset.
+func newlineOffsets(s string, offset int) []int {
+	offsets := []int{}
+	for {
+		i := strings.IndexByte(s, '\n')
+		if i < 0 {
+			return offsets
+		}
+		offset += i + 1
+		offsets = append(offsets, offset)
+		s = s[i+1:]
+	}
 }
 
-func (t *TextDocument) resolve(p lsp.Position) int {
-	for ix, c := range t.Content {
-		if p.Line == 0 {
-			if p.Character == 0 {
-				return ix
-			}
-			if c == '\n' {
-				lsp.FrameLogger("textDocument error", []byte(fmt.Sprintf("overshoot of line %v", ix)))
-				return ix
-			}
-			delta := utf16.RuneLen(c)
-			if delta == -1 || p.Character == 1 && delta == 2 {
-				lsp.FrameLogger("textDocument error", []byte(fmt.Sprintf("invalid utf-16 at %v", ix)))
-				delta = 1
-			}
-			p.Character -= uint32(delta)
-		} else if c == '\n' {
-			p.Line -= 1
-		}
+// applyChange applies an edit from the client. If the edit's range is invalid
+// it is clamped to the document, and an error is returned describing the problem.
+func (t *TextDocument) applyChange(change lsp.TextDocumentContentChangeEvent) error {
+	content := normalizeNewlines(change.Text)
+	if change.Range == nil {
+		t.setContent(content)
+		return nil
 	}
-	if p.Line != 0 && p.Character != 0 {
-		lsp.FrameLogger("textDocument error", []byte(fmt.Sprintf("overshoot")))
+	start, startErr := t.resolve(change.Range.Start)
+	end, endErr := t.resolve(change.Range.End)
+	end = max(start, end)
+	t.replace(start, end, content)
+	if startErr != nil {
+		return startErr
 	}
-	return len(t.Content)
+	return endErr
 }
 
-func (t *TextDocument) unresolve(ix int) lsp.Position {
-	p := lsp.Position{Line: 0, Character: 0}
-	for _, c := range t.Content[:ix] {
-		if c == '\n' {
-			p.Line++
-			p.Character = 0
-		} else {
-			delta := utf16.RuneLen(c)
-			if delta == -1 {
-				lsp.FrameLogger("textDocument error", []byte(fmt.Sprintf("invalid utf-16 at %v", ix)))
-				delta = 1
-			}
-			p.Character += uint32(delta)
-		}
+// replace replaces Content[start:end] with text, updating the line index
+// without rescanning the unchanged parts of the document.
+func (t *TextDocument) replace(start int, end int, text string) {
+	first, last := t.lineOf(start), t.lineOf(end)
+	delta := len(text) - (end - start)
+	inserted := newlineOffsets(text, start)
+	tail := t.lineStarts[last+1:]
+
+	lineStarts := make([]int, 0, first+1+len(inserted)+len(tail))
+	lineStarts = append(lineStarts, t.lineStarts[:first+1]...)
+	lineStarts = append(lineStarts, inserted...)
+	for _, offset := range tail {
+		lineStarts = append(lineStarts, offset+delta)
 	}
-	return p
+	t.Content = t.Content[:start] + text + t.Content[end:]
+	t.lineStarts = lineStarts
 }
 
-func indexUtf16To8(line string, utf16Pos uint32) int {
-	pos := 0
-	for ix, c := range line {
-		if pos >= int(utf16Pos) {
-			return ix
-		}
-		pos += utf16.RuneLen(c)
-	}
+// lineOf returns the (zero-based) line containing the byte offset.
+func (t *TextDocument) lineOf(offset int) int {
+	return sort.SearchInts(t.lineStarts, offset+1) - 1
+}
 
-	return len(line)
+func (t *TextDocument) lineCount() int {
+	return len(t.lineStarts)
+}
+
+// line returns the (zero-based) nth line, without its trailing newline.
+func (t *TextDocument) line(n int) string {
+	if n+1 < len(t.lineStarts) {
+		return t.Content[t.lineStarts[n] : t.lineStarts[n+1]-1]
+	}
+	return t.Content[t.lineStarts[n]:]
 }
 
-func indexUtf8To16(line string, utf8Pos int) uint32 {
-	pos := 0
-	for ix, c := range line {
-		if ix >= utf8Pos {
-			return uint32(pos)
+// resolve converts a position to a byte offset. If the position is invalid,
+// the nearest valid offset is returned along with an error.
+func (t *TextDocument) resolve(p lsp.Position) (int, error) {
+	if int(p.Line) >= len(t.lineStarts) {
+		return len(t.Content), fmt.Errorf("position %v:%v is past the end of the document", p.Line, p.Character)
+	}
+	var err error
+	start := t.lineStarts[p.Line]
+	line := t.line(int(p.Line))
+	character := p.Character
+	for ix := 0; ix < len(line); {
+		if character == 0 {
+			return start + ix, err
+		}
+		c, size := utf8.DecodeRuneInString(line[ix:])
+		delta := t.Encoding.Width(c, size)
+		if delta == -1 || int(character) < delta {
+			err = fmt.Errorf("position %v:%v is not a valid %v offset", p.Line, p.Character, t.Encoding)
+			delta = int(character)
 		}
-		pos += utf16.RuneLen(c)
+		character -= uint32(delta)
+		ix += size
+	}
+	if character == 0 {
+		return start + len(line), err
 	}
+	if int(p.Line) == len(t.lineStarts)-1 {
+		return len(t.Content), fmt.Errorf("position %v:%v is past the end of the document", p.Line, p.Character)
+	}
+	return start + len(line), fmt.Errorf("position %v:%v is past the end of the line", p.Line, p.Character)
+}
 
-	return uint32(pos)
+func (t *TextDocument) unresolve(ix int) lsp.Position {
+	line := t.lineOf(ix)
+	return lsp.Position{Line: uint32(line), Character: t.Encoding.Len(t.Content[t.lineStarts[line]:ix])}
 }
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/ConradIrwin/conl-lsp/lsp"
+)
+
+func largeDocument(lines int) *TextDocument {
+	content := &strings.Builder{}
+	for i := range lines {
+		fmt.Fprintf(content, "key%d = value with ünïcödé %d\n", i, i)
+	}
+	return NewTextDocument("file:///large.conl", 1, content.String(), "conl", lsp.PositionEncodingKindUTF16)
+}
+
+func TestApplyChange(t *testing.T) {
+	doc := NewTextDocument("file:///a.conl", 1, "a = 1\nb\n  c = 😀\n", "conl", lsp.PositionEncodingKindUTF16)
+	edit := func(startLine, startChar, endLine, endChar uint32, text string) lsp.TextDocumentContentChangeEvent {
+		return lsp.TextDocumentContentChangeEvent{
+			Range: &lsp.Range{
+				Start: lsp.Position{Line: startLine, Character: startChar},
+				End:   lsp.Position{Line: endLine, Character: endChar},
+			},
+			Text: text,
+		}
+	}
+	for _, test := range []struct {
+		change   lsp.TextDocumentContentChangeEvent
+		expected string
+	}{
+		{edit(0, 4, 0, 5, "2"), "a = 2\nb\n  c = 😀\n"},
+		{edit(1, 1, 1, 1, "\n  d\r\n  e"), "a = 2\nb\n  d\n  e\n  c = 😀\n"},
+		{edit(0, 5, 3, 3, ""), "a = 2\n  c = 😀\n"},
+		{edit(1, 6, 1, 8, "x\n"), "a = 2\n  c = x\n\n"},
+		{edit(3, 0, 3, 0, "f"), "a = 2\n  c = x\n\nf"},
+		{lsp.TextDocumentContentChangeEvent{Text: "g\nh"}, "g\nh"},
+	} {
+		if err := doc.applyChange(test.change); err != nil {
+			t.Fatal(err)
+		}
+		if doc.Content != test.expected {
+			t.Fatalf("got %#v, expected %#v", doc.Content, test.expected)
+		}
+		fresh := NewTextDocument(doc.URI, 1, doc.Content, "conl", doc.Encoding)
+		if !reflect.DeepEqual(doc.lineStarts, fresh.lineStarts) {
+			t.Fatalf("got line starts %v, expected %v for %#v", doc.lineStarts, fresh.lineStarts, doc.Content)
+		}
+		for ix := range len(doc.Content) + 1 {
+			if ix < len(doc.Content) && !utf8.RuneStart(doc.Content[ix]) {
+				continue
+			}
+			if resolved, err := doc.resolve(doc.unresolve(ix)); err == nil && resolved != ix {
+				t.Fatalf("%d resolved to %d in %#v", ix, resolved, doc.Content)
+			}
+		}
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	doc := NewTextDocument("file:///a.conl", 1, "ab\n😀\n", "conl", lsp.PositionEncodingKindUTF16)
+	for _, test := range []struct {
+		position lsp.Position
+		offset   int
+		err      string
+	}{
+		{lsp.Position{Line: 0, Character: 5}, 2, "position 0:5 is past the end of the line"},
+		{lsp.Position{Line: 1, Character: 1}, 7, "position 1:1 is not a valid utf-16 offset"},
+		{lsp.Position{Line: 2, Character: 1}, 8, "position 2:1 is past the end of the document"},
+		{lsp.Position{Line: 3, Character: 0}, 8, "position 3:0 is past the end of the document"},
+	} {
+		offset, err := doc.resolve(test.position)
+		if offset != test.offset || err == nil || err.Error() != test.err {
+			t.Errorf("resolve(%v) = %d, %v, expected %d, %s", test.position, offset, err, test.offset, test.err)
+		}
+	}
+}
+
+func BenchmarkResolve(b *testing.B) {
+	doc := largeDocument(10000)
+	for i := 0; b.Loop(); i++ {
+		doc.resolve(lsp.Position{Line: uint32(i % 10000), Character: 20})
+	}
+}
+
+func BenchmarkUnresolve(b *testing.B) {
+	doc := largeDocument(10000)
+	for i := 0; b.Loop(); i++ {
+		doc.unresolve((i * 7919) % len(doc.Content))
+	}
+}
+
+func BenchmarkApplyChange(b *testing.B) {
+	doc := largeDocument(10000)
+	for i := 0; b.Loop(); i++ {
+		line := uint32(i % 10000)
+		doc = doc.Clone()
+		doc.applyChange(lsp.TextDocumentContentChangeEvent{
+			Range: &lsp.Range{Start: lsp.Position{Line: line, Character: 3}, End: lsp.Position{Line: line, Character: 4}},
+			Text:  "x",
+		})
+	}
+}
+
+func TestResolveInvalidUTF8(t *testing.T) {
+	doc := NewTextDocument("file:///a.conl", 1, "a\xff\xe2\x82b = 1\n", "conl", lsp.PositionEncodingKindUTF8)
+	for character, offset := range []int{0, 1, 2, 3, 4} {
+		p := lsp.Position{Line: 0, Character: uint32(character)}
+		if got, err := doc.resolve(p); got != offset || err != nil {
+			t.Errorf("resolve(%v) = %v, %v, expected %v", p, got, err, offset)
+		}
+		if got := doc.unresolve(offset); got != p {
+			t.Errorf("unresolve(%v) = %v, expected %v", offset, got, p)
+		}
+	}
+}
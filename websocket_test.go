@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/ConradIrwin/conl-lsp/lsp"
+)
+
+// webSocketClient reads unmasked frames from the server as a single stream
+type webSocketClient struct {
+	r         *bufio.Reader
+	remaining int
+}
+
+func (c *webSocketClient) Read(p []byte) (int, error) {
+	for c.remaining == 0 {
+		var header [2]byte
+		if _, err := io.ReadFull(c.r, header[:]); err != nil {
+			return 0, err
+		}
+		c.remaining = int(header[1] & 0x7f)
+		if c.remaining == 126 {
+			var ext [2]byte
+			if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+				return 0, err
+			}
+			c.remaining = int(binary.BigEndian.Uint16(ext[:]))
+		}
+	}
+	n, err := c.r.Read(p[:min(len(p), c.remaining)])
+	c.remaining -= n
+	return n, err
+}
+
+func writeMaskedFrame(w io.Writer, payload []byte) error {
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{0x80 | opText, 0x80 | 126}
+	frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	_, err := w.Write(frame)
+	return err
+}
+
+func TestWebSocket(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go serveWebSocket(t.Context(), l, "/lsp", nil)
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	fmt.Fprintf(conn, "GET /lsp HTTP/1.1\r\nHost: localhost\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n"+
+		"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n")
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("invalid handshake response: %#v", resp)
+	}
+
+	msg := []byte(`{"jsonrpc":"2.0","id":0,"method":"initialize","params":{}}`)
+	if err := writeMaskedFrame(conn, append([]byte(fmt.Sprintf("Content-Length: %d\r\n\r\n", len(msg))), msg...)); err != nil {
+		t.Fatal(err)
+	}
+
+	next, stop := iter.Pull2(lsp.ReadFrames(&webSocketClient{r: br}))
+	defer stop()
+	frame, err, ok := next()
+	if !ok || err != nil {
+		t.Fatalf("no response received: %v", err)
+	}
+	result := lsp.InitializeResult{}
+	if err := json.Unmarshal(frame.Result, &result); err != nil {
+		t.Fatal(err)
+	}
+	if string(frame.Id) != "0" || result.Capabilities.HoverProvider != true {
+		t.Fatalf("invalid response: %#v", frame)
+	}
+}
+
+func TestWebSocketOrigin(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go serveWebSocket(t.Context(), l, "/lsp", []string{"https://allowed.example"})
+
+	for origin, expected := range map[string]int{
+		"":                        http.StatusSwitchingProtocols,
+		"http://localhost:8080":   http.StatusSwitchingProtocols,
+		"https://allowed.example": http.StatusSwitchingProtocols,
+		"https://evil.example":    http.StatusForbidden,
+		"http://localhost:9090":   http.StatusForbidden,
+	} {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		header := ""
+		if origin != "" {
+			header = "Origin: " + origin + "\r\n"
+		}
+		fmt.Fprintf(conn, "GET /lsp HTTP/1.1\r\nHost: localhost:8080\r\n%sConnection: Upgrade\r\nUpgrade: websocket\r\n"+
+			"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n", header)
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		conn.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != expected {
+			t.Fatalf("origin %#v: got status %v, expected %v", origin, resp.StatusCode, expected)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/ConradIrwin/conl-lsp/lsp"
+)
+
+// maxWorkspaceSymbols limits the results of a workspace/symbol query, as
+// clients query again as the user types.
+const maxWorkspaceSymbols = 250
+
+// A workspaceIndex holds the key paths of every .conl file in the workspace,
+// so that workspace/symbol can search files that are not open.
+type workspaceIndex struct {
+	mutex sync.RWMutex
+	files map[lsp.DocumentURI]*indexedFile
+}
+
+type indexedFile struct {
+	// open is set if the symbols came from an open document, which takes
+	// precedence over the file on disk until it is closed.
+	open bool
+	// doc is set if symbols have not yet been found for an open document.
+	// Open documents change on every keystroke, so they are only indexed
+	// when searched.
+	doc     *TextDocument
+	symbols []lsp.SymbolInformation
+}
+
+func newWorkspaceIndex() *workspaceIndex {
+	return &workspaceIndex{files: map[lsp.DocumentURI]*indexedFile{}}
+}
+
+// set replaces the symbols for doc. Files read from disk do not replace
+// open documents, and open documents are indexed by the next search.
+func (w *workspaceIndex) set(doc *TextDocument, open bool) {
+	if open {
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+		w.files[doc.URI] = &indexedFile{open: true, doc: doc}
+		return
+	}
+	symbols := keyPaths(doc)
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if existing, ok := w.files[doc.URI]; ok && existing.open {
+		return
+	}
+	w.files[doc.URI] = &indexedFile{symbols: symbols}
+}
+
+// close marks the document at uri as closed, so that it can be replaced by
+// the file on disk.
+func (w *workspaceIndex) close(uri lsp.DocumentURI) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if existing, ok := w.files[uri]; ok {
+		existing.open = false
+	}
+}
+
+// remove removes the file at uri from the index, unless it is open.
+func (w *workspaceIndex) remove(uri lsp.DocumentURI) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if existing, ok := w.files[uri]; ok && !existing.open {
+		delete(w.files, uri)
+	}
+}
+
+// search returns the symbols whose key path matches query, best first.
+func (w *workspaceIndex) search(query string) []lsp.SymbolInformation {
+	type match struct {
+		score  int
+		symbol *lsp.SymbolInformation
+	}
+	matches := []match{}
+	w.mutex.Lock()
+	for _, file := range w.files {
+		if file.doc != nil {
+			file.symbols = keyPaths(file.doc)
+			file.doc = nil
+		}
+		for i := range file.symbols {
+			if score, ok := fuzzyScore(query, file.symbols[i].Name); ok {
+				matches = append(matches, match{score, &file.symbols[i]})
+			}
+		}
+	}
+	w.mutex.Unlock()
+
+	slices.SortFunc(matches, func(a, b match) int {
+		return cmp.Or(
+			cmp.Compare(b.score, a.score),
+			cmp.Compare(len(a.symbol.Name), len(b.symbol.Name)),
+			strings.Compare(a.symbol.Name, b.symbol.Name),
+			strings.Compare(string(a.symbol.Location.URI), string(b.symbol.Location.URI)),
+			cmp.Compare(a.symbol.Location.Range.Start.Line, b.symbol.Location.Range.Start.Line),
+		)
+	})
+	symbols := []lsp.SymbolInformation{}
+	for _, m := range matches[:min(len(matches), maxWorkspaceSymbols)] {
+		symbols = append(symbols, *m.symbol)
+	}
+	return symbols
+}
+
+// keyPaths returns a symbol for each key and list item in doc, named by its
+// dotted path from the root of the document (e.g. database.replicas).
+func keyPaths(doc *TextDocument) []lsp.SymbolInformation {
+	paths := []lsp.SymbolInformation{}
+	var walk func(container string, symbols []lsp.DocumentSymbol)
+	walk = func(container string, symbols []lsp.DocumentSymbol) {
+		for _, symbol := range symbols {
+			name := symbol.Name
+			if container != "" {
+				name = container + "." + name
+			}
+			paths = append(paths, lsp.SymbolInformation{
+				BaseSymbolInformation: lsp.BaseSymbolInformation{
+					Name:          name,
+					Kind:          symbol.Kind,
+					ContainerName: container,
+				},
+				Location: lsp.Location{URI: doc.URI, Range: symbol.Range},
+			})
+			walk(name, symbol.Children)
+		}
+	}
+	walk("", documentSymbols(doc, nil))
+	return paths
+}
+
+// fuzzyScore reports whether the characters of query appear in order in
+// candidate, ignoring case. Matches score higher when the characters are
+// consecutive, or start a segment of the key path.
+func fuzzyScore(query, candidate string) (int, bool) {
+	query = strings.ToLower(query)
+	candidate = strings.ToLower(candidate)
+	score := 0
+	pos, previous := 0, -1
+	for _, r := range query {
+		i := strings.IndexRune(candidate[pos:], r)
+		if i < 0 {
+			return 0, false
+		}
+		i += pos
+		score++
+		if i == previous {
+			score += 2
+		}
+		if i == 0 || strings.IndexByte("._- ", candidate[i-1]) >= 0 {
+			score += 3
+		}
+		// invalid UTF-8 decodes as one byte, not the length of RuneError
+		_, size := utf8.DecodeRuneInString(candidate[i:])
+		pos = i + size
+		previous = pos
+	}
+	if query != "" && strings.Contains(candidate, query) {
+		score += len(query)
+	}
+	return score, true
+}
+
+func (s *Server) workspaceSymbol(ctx context.Context, params *lsp.WorkspaceSymbolParams) ([]lsp.SymbolInformation, error) {
+	return s.index.search(params.Query), nil
+}
+
+func (s *Server) workspaceDidChangeWatchedFiles(ctx context.Context, params *lsp.DidChangeWatchedFilesParams) {
+	for _, change := range params.Changes {
+		if filepath.Ext(change.URI.URL().Path) != ".conl" {
+			continue
+		}
+		if change.Type == lsp.FileChangeTypeDeleted {
+			s.index.remove(change.URI)
+		} else {
+			s.indexFile(change.URI)
+		}
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.documentsChanged()
+}
+
+// indexWorkspace adds every .conl file in the workspace to the index.
+// It runs in the background after initialization, and stops early if the
+// server exits.
+func (s *Server) indexWorkspace() {
+	for _, uri := range s.workspaceFiles() {
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+		s.indexFile(uri)
+	}
+}
+
+// indexFile updates the index for the file at uri, which is read from disk
+// unless it is open.
+func (s *Server) indexFile(uri lsp.DocumentURI) {
+	snap := s.snapshot()
+	doc, err := s.document(snap, uri)
+	if err != nil {
+		s.logger.Debug("failed to index file", "uri", uri, "error", err)
+		s.index.remove(uri)
+		return
+	}
+	_, open := snap.docs[uri]
+	s.index.set(doc, open)
+}
+
+// watchFiles asks the client to send workspace/didChangeWatchedFiles for
+// .conl files, so that the index includes changes made outside the editor.
+func (s *Server) watchFiles() {
+	options, err := json.Marshal(lsp.DidChangeWatchedFilesRegistrationOptions{
+		Watchers: []lsp.FileSystemWatcher{{GlobPattern: json.RawMessage(`"**/*.conl"`)}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	err = s.c.Request(context.Background(), "client/registerCapability", &lsp.RegistrationParams{
+		Registrations: []lsp.Registration{{
+			ID:              "conl-watched-files",
+			Method:          "workspace/didChangeWatchedFiles",
+			RegisterOptions: options,
+		}},
+	}, nil)
+	if err != nil {
+		s.logger.Warn("failed to watch files", "error", err)
+	}
+}
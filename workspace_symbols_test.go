@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ConradIrwin/conl-lsp/lsp"
+)
+
+func symbolNames(symbols []lsp.SymbolInformation) []string {
+	names := []string{}
+	for _, symbol := range symbols {
+		names = append(names, symbol.Name)
+	}
+	return names
+}
+
+func TestFuzzyScore(t *testing.T) {
+	for _, candidate := range []string{"database.replicas", "Database.Replicas", "db.replicas"} {
+		if _, ok := fuzzyScore("dbrep", candidate); !ok {
+			t.Errorf("expected dbrep to match %#v", candidate)
+		}
+	}
+	if _, ok := fuzzyScore("repd", "database.replicas"); ok {
+		t.Error("expected characters to match in order")
+	}
+	if _, ok := fuzzyScore("\xffa", "\xffa"); !ok {
+		t.Error("expected invalid UTF-8 to match itself")
+	}
+	exact, _ := fuzzyScore("replicas", "database.replicas")
+	scattered, _ := fuzzyScore("replicas", "database.read.pool.limits.capacity.size")
+	if exact <= scattered {
+		t.Errorf("expected a contiguous match to score higher: %v <= %v", exact, scattered)
+	}
+}
+
+func TestWorkspaceIndexSearch(t *testing.T) {
+	index := newWorkspaceIndex()
+	index.set(NewTextDocument("file:///a.conl", 0, "database\n  replicas = 3\n  primary = a\n", "conl", lsp.PositionEncodingKindUTF16), false)
+	index.set(NewTextDocument("file:///b.conl", 0, "replicas = 2\ndatabases\n  = main\n", "conl", lsp.PositionEncodingKindUTF16), false)
+
+	if names, expected := symbolNames(index.search("database.replicas")), []string{"database.replicas"}; !slices.Equal(names, expected) {
+		t.Fatalf("got %#v, expected %#v", names, expected)
+	}
+	if names, expected := symbolNames(index.search("replicas")), []string{"replicas", "database.replicas"}; !slices.Equal(names, expected) {
+		t.Fatalf("got %#v, expected %#v", names, expected)
+	}
+	if names, expected := symbolNames(index.search("dbs0")), []string{"databases.0"}; !slices.Equal(names, expected) {
+		t.Fatalf("got %#v, expected %#v", names, expected)
+	}
+
+	// open documents take precedence over the file on disk until closed
+	index.set(NewTextDocument("file:///a.conl", 1, "cache = 1\n", "conl", lsp.PositionEncodingKindUTF16), true)
+	index.set(NewTextDocument("file:///a.conl", 0, "database = 1\n", "conl", lsp.PositionEncodingKindUTF16), false)
+	index.remove("file:///a.conl")
+	if names := symbolNames(index.search("cache")); !slices.Equal(names, []string{"cache"}) {
+		t.Fatalf("got %#v, expected the open document", names)
+	}
+	// edits to open documents are indexed by the next search
+	index.set(NewTextDocument("file:///a.conl", 2, "cached = 1\n", "conl", lsp.PositionEncodingKindUTF16), true)
+	if names := symbolNames(index.search("cache")); !slices.Equal(names, []string{"cached"}) {
+		t.Fatalf("got %#v, expected the edited document", names)
+	}
+	index.close("file:///a.conl")
+	index.remove("file:///a.conl")
+	if names := symbolNames(index.search("cached")); len(names) != 0 {
+		t.Fatalf("got %#v, expected the closed document to be removed", names)
+	}
+}
+
+func TestWorkspaceSymbol(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := lsp.DocumentURI("file://" + wd + "/testdata")
+	uri, server := newTestServerWith(t, lsp.InitializeParams{
+		WorkspaceFoldersInitializeParams: lsp.WorkspaceFoldersInitializeParams{
+			WorkspaceFolders: []lsp.WorkspaceFolder{{URI: root, Name: "testdata"}},
+		},
+	}, "database\n  replicas = 3\n")
+	testNotify(server, "initialized", lsp.InitializedParams{})
+
+	symbols := *testRequest[[]lsp.SymbolInformation](server, "workspace/symbol", lsp.WorkspaceSymbolParams{Query: "database.replicas"})
+	if len(symbols) != 1 || symbols[0].Location.URI != uri || symbols[0].Location.Range.Start.Line != 1 {
+		t.Fatalf("got %#v, expected database.replicas in the open document", symbols)
+	}
+
+	// files on disk are indexed in the background
+	deadline := time.Now().Add(time.Second)
+	for {
+		symbols = *testRequest[[]lsp.SymbolInformation](server, "workspace/symbol", lsp.WorkspaceSymbolParams{Query: "definitions.value"})
+		if len(symbols) > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the workspace to be indexed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if symbols[0].Location.URI != root+"/completions.conl" {
+		t.Fatalf("got %#v, expected definitions.value in completions.conl", symbols[0])
+	}
+}